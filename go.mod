@@ -2,4 +2,15 @@ module github.com/jvnonce/jv-go-utils
 
 go 1.22
 
-require github.com/iancoleman/strcase v0.3.0
+require (
+	github.com/iancoleman/strcase v0.3.0
+	golang.org/x/crypto v0.31.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142
+	google.golang.org/grpc v1.67.3
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/sys v0.28.0 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+)
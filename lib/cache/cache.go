@@ -0,0 +1,272 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+)
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type cache[K comparable, V any] struct {
+	config
+	mu       sync.Mutex
+	items    map[K]*list.Element
+	lru      *list.List
+	calls    map[K]*call[V]
+	onExpire func(key K, value V)
+	done     chan struct{}
+	once     sync.Once
+}
+
+// In-memory cache with expiration of entries and LRU eviction. Safe for concurrent use
+type Cache[K comparable, V any] interface {
+	// Returns value of not expired entry
+	Get(key K) (V, bool)
+	// Sets value with default TTL
+	Set(key K, value V)
+	// Sets value with the given TTL. Zero TTL means entry doesn't expire
+	SetTTL(key K, value V, ttl time.Duration)
+	// Returns cached value or loads and caches it with default TTL.
+	// Concurrent calls for the same key share one load. Errors are not cached.
+	// Load gets context of the call which started it
+	//
+	// Ex.: user, err := c.GetOrLoad(ctx, id, repo.LoadUser)
+	GetOrLoad(ctx context.Context, key K, load func(ctx context.Context, key K) (V, error)) (V, error)
+	// Removes entry. Returns false if there was no entry
+	Delete(key K) bool
+	// Count of entries including expired ones not removed yet
+	Len() int
+	// Keys of not expired entries from the most to the least recently used
+	Keys() []K
+	// Removes all entries
+	Clear()
+	// Removes expired entries calling expiration callback
+	Purge()
+	// Stops background cleanup
+	Close()
+}
+
+// Cache constructor
+//
+// Ex.: cache.New[int, User](cache.WithTTL(5*time.Minute), cache.WithMaxSize(10000))
+func New[K comparable, V any](opts ...Option) Cache[K, V] {
+	c := &cache[K, V]{
+		config: newConfig[K, V](opts),
+		items:  make(map[K]*list.Element),
+		lru:    list.New(),
+		calls:  make(map[K]*call[V]),
+		done:   make(chan struct{}),
+	}
+	c.onExpire, _ = c.config.onExpire.(func(key K, value V))
+	if c.cleanup > 0 {
+		go c.clean()
+	}
+	return c
+}
+
+func (c *cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	el, ok := c.items[key]
+	if !ok {
+		c.mu.Unlock()
+		var zero V
+		return zero, false
+	}
+	e := el.Value.(*entry[K, V])
+	if e.expired(time.Now()) {
+		c.removeElement(el)
+		c.mu.Unlock()
+		c.expire([]*entry[K, V]{e})
+		var zero V
+		return zero, false
+	}
+	c.lru.MoveToFront(el)
+	c.mu.Unlock()
+	return e.value, true
+}
+
+func (c *cache[K, V]) Set(key K, value V) {
+	c.SetTTL(key, value, c.ttl)
+}
+
+func (c *cache[K, V]) SetTTL(key K, value V, ttl time.Duration) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[K, V])
+		e.value = value
+		e.expiresAt = expiresAt
+		c.lru.MoveToFront(el)
+		c.mu.Unlock()
+		return
+	}
+	c.items[key] = c.lru.PushFront(&entry[K, V]{
+		key:       key,
+		value:     value,
+		expiresAt: expiresAt,
+	})
+	var expired []*entry[K, V]
+	if c.maxSize > 0 {
+		now := time.Now()
+		for c.lru.Len() > c.maxSize {
+			el := c.lru.Back()
+			c.removeElement(el)
+			if e := el.Value.(*entry[K, V]); e.expired(now) {
+				expired = append(expired, e)
+			}
+		}
+	}
+	c.mu.Unlock()
+	c.expire(expired)
+}
+
+func (c *cache[K, V]) GetOrLoad(ctx context.Context, key K, load func(ctx context.Context, key K) (V, error)) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+
+	c.mu.Lock()
+	cl, ok := c.calls[key]
+	if !ok {
+		cl = &call[V]{done: make(chan struct{})}
+		c.calls[key] = cl
+		c.mu.Unlock()
+		c.load(ctx, key, cl, load)
+		return cl.value, cl.err
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-cl.done:
+		return cl.value, cl.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+func (c *cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if ok {
+		c.removeElement(el)
+	}
+	return ok
+}
+
+func (c *cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *cache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	result := make([]K, 0, c.lru.Len())
+	for el := c.lru.Front(); el != nil; el = el.Next() {
+		if e := el.Value.(*entry[K, V]); !e.expired(now) {
+			result = append(result, e.key)
+		}
+	}
+	return result
+}
+
+func (c *cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[K]*list.Element)
+	c.lru.Init()
+}
+
+func (c *cache[K, V]) Purge() {
+	c.mu.Lock()
+	now := time.Now()
+	var expired []*entry[K, V]
+	for el := c.lru.Front(); el != nil; {
+		next := el.Next()
+		if e := el.Value.(*entry[K, V]); e.expired(now) {
+			c.removeElement(el)
+			expired = append(expired, e)
+		}
+		el = next
+	}
+	c.mu.Unlock()
+	c.expire(expired)
+}
+
+func (c *cache[K, V]) Close() {
+	c.once.Do(func() {
+		close(c.done)
+	})
+}
+
+// Calls load and caches its result. Panic of load is returned as error to all waiters
+func (c *cache[K, V]) load(ctx context.Context, key K, cl *call[V], load func(ctx context.Context, key K) (V, error)) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, key)
+		c.mu.Unlock()
+		close(cl.done)
+	}()
+	cl.err = jve.Safe(func() error {
+		var err error
+		cl.value, err = load(ctx, key)
+		return err
+	})
+	if cl.err == nil {
+		c.Set(key, cl.value)
+	}
+}
+
+func (c *cache[K, V]) clean() {
+	t := time.NewTicker(c.cleanup)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			c.Purge()
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// Must be called with locked mutex
+func (c *cache[K, V]) removeElement(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.items, el.Value.(*entry[K, V]).key)
+}
+
+// Calls expiration callback. Must be called without locked mutex
+func (c *cache[K, V]) expire(entries []*entry[K, V]) {
+	if c.onExpire == nil {
+		return
+	}
+	for _, e := range entries {
+		c.onExpire(e.key, e.value)
+	}
+}
+
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
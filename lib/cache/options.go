@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+type config struct {
+	ttl      time.Duration
+	maxSize  int
+	cleanup  time.Duration
+	onExpire any
+}
+
+// Option of cache
+type Option func(*config)
+
+// Default time to live of entries. Zero means entries don't expire
+func WithTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+// Maximum count of entries. Least recently used entry is evicted when limit is exceeded.
+// Zero means no limit
+func WithMaxSize(n int) Option {
+	return func(c *config) {
+		c.maxSize = n
+	}
+}
+
+// Removes expired entries in background once per interval.
+// Without it expired entries are removed when they are accessed or evicted.
+// Cache must be closed to stop cleanup
+func WithCleanup(interval time.Duration) Option {
+	return func(c *config) {
+		c.cleanup = interval
+	}
+}
+
+// Calls fn for every expired entry when it is removed from cache.
+// Types of key and value must match the types of cache, otherwise constructor of cache panics
+//
+// Ex.: cache.New[string, *Session](cache.WithTTL(time.Hour), cache.OnExpire(func(id string, s *Session) { s.Close() }))
+func OnExpire[K comparable, V any](fn func(key K, value V)) Option {
+	return func(c *config) {
+		c.onExpire = fn
+	}
+}
+
+// Applies options of cache with keys K and values V. Panics if OnExpire callback is set for other types
+func newConfig[K comparable, V any](opts []Option) config {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.onExpire != nil {
+		if _, ok := c.onExpire.(func(key K, value V)); !ok {
+			panic(fmt.Sprintf("cache: OnExpire callback %T doesn't match cache types %v and %v", c.onExpire, reflect.TypeFor[K](), reflect.TypeFor[V]()))
+		}
+	}
+	return c
+}
@@ -1,5 +1,11 @@
 package chains
 
+import (
+	"context"
+	"slices"
+	"sort"
+)
+
 // Applies function for the collection.
 // If chainfunc returns false, chain will be stopped
 func ChainForEach[A any](collection []A, chainfunc func(int, A) bool) {
@@ -10,6 +16,20 @@ func ChainForEach[A any](collection []A, chainfunc func(int, A) bool) {
 	}
 }
 
+// Applies function for the collection checking context between elements.
+// Chain stops on the first error or when context is done
+func ChainForEachCtx[A any](ctx context.Context, collection []A, chainfunc func(context.Context, int, A) error) error {
+	for i, v := range collection {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := chainfunc(ctx, i, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Applies function for the collection. Returns collection of the same type
 func ChainMap[A any](collection []A, chainfunc func(int, A) A) []A {
 	result := make([]A, len(collection))
@@ -56,3 +76,53 @@ func ChainIndexOf[A comparable](collection []A, value A) int {
 	}
 	return -1
 }
+
+// Sorts copy of the collection with comparison function. Sorting is stable.
+//
+// Ex.: chains.ChainSortBy(users, chains.By(func(u User) string { return u.Name }).Desc())
+func ChainSortBy[A any](collection []A, cmp func(A, A) int) []A {
+	result := slices.Clone(collection)
+	slices.SortStableFunc(result, cmp)
+	return result
+}
+
+// Searching value in the sorted collection using binary search.
+// Returns index of element and true if found, otherwise insertion index and false.
+// cmp returns negative, zero or positive value like strings.Compare
+func ChainSearch[A any](sorted []A, target A, cmp func(A, A) int) (int, bool) {
+	index := ChainLowerBound(sorted, target, cmp)
+	return index, index < len(sorted) && cmp(sorted[index], target) == 0
+}
+
+// Returns index of the first element of the sorted collection which is not less than value
+func ChainLowerBound[A any](sorted []A, value A, cmp func(A, A) int) int {
+	return sort.Search(len(sorted), func(i int) bool {
+		return cmp(sorted[i], value) >= 0
+	})
+}
+
+// Returns index of the first element of the sorted collection which is greater than value
+func ChainUpperBound[A any](sorted []A, value A, cmp func(A, A) int) int {
+	return sort.Search(len(sorted), func(i int) bool {
+		return cmp(sorted[i], value) > 0
+	})
+}
+
+// Pair of values
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Returns consecutive pairs of elements of the collection.
+// Collection with less than two elements produces empty result
+func ChainPairwise[A any](collection []A) []Pair[A, A] {
+	if len(collection) < 2 {
+		return make([]Pair[A, A], 0)
+	}
+	result := make([]Pair[A, A], len(collection)-1)
+	for i := 1; i < len(collection); i++ {
+		result[i-1] = Pair[A, A]{First: collection[i-1], Second: collection[i]}
+	}
+	return result
+}
@@ -0,0 +1,33 @@
+package chains
+
+import "cmp"
+
+// Comparison function. Returns negative, zero or positive value
+// if the first argument is less, equal or greater than the second one
+type Comparator[A any] func(A, A) int
+
+// Comparator by key of element in ascending order
+//
+// Ex.: chains.By(func(u User) string { return u.Name }).Desc().Then(chains.By(func(u User) int { return u.ID }))
+func By[A any, K cmp.Ordered](key func(A) K) Comparator[A] {
+	return func(a, b A) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+// Reverses order of comparator
+func (c Comparator[A]) Desc() Comparator[A] {
+	return func(a, b A) int {
+		return c(b, a)
+	}
+}
+
+// Uses next comparator when elements are equal by current one
+func (c Comparator[A]) Then(next Comparator[A]) Comparator[A] {
+	return func(a, b A) int {
+		if result := c(a, b); result != 0 {
+			return result
+		}
+		return next(a, b)
+	}
+}
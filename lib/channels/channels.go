@@ -0,0 +1,94 @@
+package channels
+
+import (
+	"sync"
+	"time"
+)
+
+// Merges values of several channels into one channel.
+// Result channel is closed when all source channels are closed
+func FanIn[T any](chs ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(chs))
+	for _, ch := range chs {
+		go func(ch <-chan T) {
+			defer wg.Done()
+			for v := range ch {
+				out <- v
+			}
+		}(ch)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Distributes values of the channel between n channels.
+// Every value is delivered to exactly one of them, whichever is ready first.
+// Result channels are closed when source channel is closed. n less than 1 is treated as 1
+func FanOut[T any](ch <-chan T, n int) []<-chan T {
+	result := make([]<-chan T, max(n, 1))
+	for i := range result {
+		out := make(chan T)
+		result[i] = out
+		go func() {
+			defer close(out)
+			for v := range ch {
+				out <- v
+			}
+		}()
+	}
+	return result
+}
+
+// Groups values of the channel into batches.
+// Batch is sent when it reaches size or when maxWait passed since its first value.
+// Zero maxWait disables the time limit. Remaining values are sent when source channel is closed
+//
+// Ex.: for events := range channels.Batch(ch, 100, time.Second) { ... }
+func Batch[T any](ch <-chan T, size int, maxWait time.Duration) <-chan []T {
+	if size < 1 {
+		size = 1
+	}
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		batch := make([]T, 0, size)
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		flush := func() {
+			if timer != nil {
+				timer.Stop()
+				timer, timeout = nil, nil
+			}
+			if len(batch) > 0 {
+				out <- batch
+				batch = make([]T, 0, size)
+			}
+		}
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok {
+					flush()
+					return
+				}
+				batch = append(batch, v)
+				if len(batch) == 1 && maxWait > 0 {
+					timer = time.NewTimer(maxWait)
+					timeout = timer.C
+				}
+				if len(batch) >= size {
+					flush()
+				}
+			case <-timeout:
+				timer, timeout = nil, nil
+				flush()
+			}
+		}
+	}()
+	return out
+}
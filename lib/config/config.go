@@ -0,0 +1,344 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jvnonce/jv-go-utils/lib/env"
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+	"github.com/jvnonce/jv-go-utils/lib/validate"
+	"gopkg.in/yaml.v3"
+)
+
+type source func(tree jvm.M) error
+
+type config struct {
+	tree jvm.M
+}
+
+// Option of config. Sources are applied in order, later ones take precedence
+type Option func(*[]source)
+
+// Configuration tree merged from defaults, files and environment.
+// Values are addressed by dot paths. Safe for concurrent reads
+type Config interface {
+	// Returns value at path
+	//
+	// Ex.: cfg.Get("db.host")
+	Get(path string) (any, bool)
+	// Returns value at path as string or default value
+	String(path string, defaultValue string) string
+	// Returns value at path as integer or default value
+	Int(path string, defaultValue int64) int64
+	// Returns value at path as float or default value
+	Float(path string, defaultValue float64) float64
+	// Returns value at path as bool or default value
+	Bool(path string, defaultValue bool) bool
+	// Returns value at path parsed by time.ParseDuration or default value
+	Duration(path string, defaultValue time.Duration) time.Duration
+	// Returns subtree at path or nil
+	Sub(path string) jvm.M
+	// Returns copy of whole tree
+	Map() jvm.M
+	// Decodes whole tree into struct by json tags and validates it by validate.Value
+	//
+	// Ex.: var cfg AppConfig; err := c.Unmarshal(&cfg)
+	Unmarshal(v any) error
+	// Decodes subtree at path into v
+	UnmarshalKey(path string, v any) error
+}
+
+// Loads config from sources
+//
+// Ex.: config.New(config.WithDefaults(jvm.M{"db": jvm.M{"port": 5432}}), config.WithFile("config.yaml"), config.WithEnv("APP"))
+func New(opts ...Option) (Config, error) {
+	var sources []source
+	for _, opt := range opts {
+		opt(&sources)
+	}
+	tree := jvm.New()
+	for _, src := range sources {
+		if err := src(tree); err != nil {
+			return nil, err
+		}
+	}
+	return &config{tree: tree}, nil
+}
+
+// Default values
+func WithDefaults(defaults jvm.M) Option {
+	return add(func(tree jvm.M) error {
+		merge(tree, normalize(defaults).(jvm.M))
+		return nil
+	})
+}
+
+// YAML or JSON file chosen by extension .yaml, .yml or .json
+func WithFile(path string) Option {
+	return add(func(tree jvm.M) error {
+		return loadFile(tree, path, false)
+	})
+}
+
+// File like WithFile which is skipped if it does not exist
+func WithOptionalFile(path string) Option {
+	return add(func(tree jvm.M) error {
+		return loadFile(tree, path, true)
+	})
+}
+
+// Overrides values by environment variables named by prefix and path in upper case
+// with dots replaced by underscores. Only paths present in previous sources are overridden,
+// value keeps type of the overridden one
+//
+// Ex.: APP_DB_MAX_CONNS=20 overrides db.max_conns with config.WithEnv("APP")
+func WithEnv(prefix string) Option {
+	return add(func(tree jvm.M) error {
+		if prefix != "" {
+			prefix += "_"
+		}
+		overrideEnv(tree, prefix)
+		return nil
+	})
+}
+
+func (c *config) Get(path string) (any, bool) {
+	var current any = c.tree
+	for _, key := range strings.Split(path, ".") {
+		m, ok := current.(jvm.M)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func (c *config) String(path string, defaultValue string) string {
+	v, ok := c.Get(path)
+	if !ok || v == nil {
+		return defaultValue
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+func (c *config) Int(path string, defaultValue int64) int64 {
+	v, ok := c.Get(path)
+	if !ok {
+		return defaultValue
+	}
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case int64:
+		return n
+	case uint64:
+		return int64(n)
+	case float64:
+		return int64(n)
+	case string:
+		if i, err := strconv.ParseInt(n, 0, 64); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+func (c *config) Float(path string, defaultValue float64) float64 {
+	v, ok := c.Get(path)
+	if !ok {
+		return defaultValue
+	}
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case uint64:
+		return float64(n)
+	case float64:
+		return n
+	case string:
+		if f, err := strconv.ParseFloat(n, 64); err == nil {
+			return f
+		}
+	}
+	return defaultValue
+}
+
+func (c *config) Bool(path string, defaultValue bool) bool {
+	v, ok := c.Get(path)
+	if !ok {
+		return defaultValue
+	}
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		if parsed, err := strconv.ParseBool(b); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func (c *config) Duration(path string, defaultValue time.Duration) time.Duration {
+	s, ok := c.Get(path)
+	if !ok {
+		return defaultValue
+	}
+	if str, ok := s.(string); ok {
+		if d, err := time.ParseDuration(str); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+func (c *config) Sub(path string) jvm.M {
+	v, _ := c.Get(path)
+	m, ok := v.(jvm.M)
+	if !ok {
+		return nil
+	}
+	return normalize(m).(jvm.M)
+}
+
+func (c *config) Map() jvm.M {
+	return normalize(c.tree).(jvm.M)
+}
+
+func (c *config) Unmarshal(v any) error {
+	return decode(c.tree, v)
+}
+
+func (c *config) UnmarshalKey(path string, v any) error {
+	value, ok := c.Get(path)
+	if !ok {
+		return fmt.Errorf("%w: config path %q", jve.ErrNotFound, path)
+	}
+	return decode(value, v)
+}
+
+func add(src source) Option {
+	return func(sources *[]source) {
+		*sources = append(*sources, src)
+	}
+}
+
+func loadFile(tree jvm.M, path string, optional bool) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if optional && os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var data map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &data)
+	case ".json":
+		err = json.Unmarshal(b, &data)
+	default:
+		return fmt.Errorf("%w: unknown config file type %q", jve.ErrBadFormat, path)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: config file %q: %v", jve.ErrBadFormat, path, err)
+	}
+	merge(tree, normalize(data).(jvm.M))
+	return nil
+}
+
+func decode(value any, v any) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%w: %v", jve.ErrBadFormat, err)
+	}
+	return validate.Value(v)
+}
+
+// Merges src into dst recursively. Values of src take precedence
+func merge(dst jvm.M, src jvm.M) {
+	for key, value := range src {
+		if sm, ok := value.(jvm.M); ok {
+			if dm, ok := dst[key].(jvm.M); ok {
+				merge(dm, sm)
+				continue
+			}
+			value = normalize(sm)
+		}
+		dst[key] = value
+	}
+}
+
+func overrideEnv(tree jvm.M, prefix string) {
+	for key, value := range tree {
+		name := prefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+		switch v := value.(type) {
+		case jvm.M:
+			overrideEnv(v, name+"_")
+		case bool:
+			tree[key] = env.Bool(name, v)
+		case int:
+			tree[key] = int(env.Int64(name, int64(v)))
+		case int64:
+			tree[key] = env.Int64(name, v)
+		case uint64:
+			tree[key] = env.Uint64(name, v)
+		case float64:
+			tree[key] = env.Float64(name, v)
+		case string:
+			tree[key] = env.String(name, v)
+		default:
+			if s, ok := os.LookupEnv(name); ok {
+				tree[key] = s
+			}
+		}
+	}
+}
+
+// Copies value converting nested maps into jvm.M
+func normalize(value any) any {
+	switch v := value.(type) {
+	case jvm.M:
+		return normalizeMap(v)
+	case map[string]any:
+		return normalizeMap(v)
+	case map[any]any:
+		m := make(jvm.M, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = normalize(item)
+		}
+		return m
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = normalize(item)
+		}
+		return result
+	}
+	return value
+}
+
+func normalizeMap(source map[string]any) jvm.M {
+	m := make(jvm.M, len(source))
+	for key, item := range source {
+		m[key] = normalize(item)
+	}
+	return m
+}
@@ -0,0 +1,105 @@
+package csvutil
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+)
+
+// Reads CSV with header and calls fn for every row as map of header to value.
+// Iteration stops on the first error of fn, which is returned
+//
+// Ex.: err := csvutil.ReadMaps(file, func(row jvm.M) error { return qb.New(db).Insert("users").ColsWithParams(row).Exec() })
+func ReadMaps(r io.Reader, fn func(row jvm.M) error) error {
+	return readRecords(r, func(header []string, record []string, _ *csv.Reader) error {
+		row := make(jvm.M, len(header))
+		for i, name := range header {
+			row[name] = record[i]
+		}
+		return fn(row)
+	})
+}
+
+// Reads records after header. Reader is passed to fn for positions of fields
+func readRecords(r io.Reader, fn func(header []string, record []string, cr *csv.Reader) error) error {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return fmt.Errorf("%w: %v", jve.ErrBadFormat, err)
+	}
+	header = append([]string(nil), header...)
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", jve.ErrBadFormat, err)
+		}
+		if err := fn(header, record, cr); err != nil {
+			return err
+		}
+	}
+}
+
+// Reads all rows of CSV with header as maps
+func ReadAllMaps(r io.Reader) ([]jvm.M, error) {
+	result := make([]jvm.M, 0)
+	err := ReadMaps(r, func(row jvm.M) error {
+		result = append(result, row)
+		return nil
+	})
+	return result, err
+}
+
+// Reads CSV with header and calls fn for every row decoded into struct.
+// Columns are matched with fields by csv tags or names of fields, unknown columns are skipped.
+// Fields of strings, numbers, bools, time.Duration, pointers and encoding.TextUnmarshaler are supported
+//
+// Ex.: type User struct { Name string `csv:"name"`; Age int `csv:"age"` }
+// err := csvutil.Read(file, func(u User) error { return save(u) })
+func Read[T any](r io.Reader, fn func(item T) error) error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %s is not a struct", jve.ErrBadType, t)
+	}
+	byName := make(map[string]int)
+	for _, f := range structFields(t) {
+		byName[f.name] = f.index
+	}
+
+	return readRecords(r, func(header []string, record []string, cr *csv.Reader) error {
+		var item T
+		v := reflect.ValueOf(&item).Elem()
+		for i, name := range header {
+			index, ok := byName[name]
+			if !ok {
+				continue
+			}
+			if err := setField(v.Field(index), record[i]); err != nil {
+				line, _ := cr.FieldPos(i)
+				return fmt.Errorf("%w: line %d column %q: %v", jve.ErrBadFormat, line, name, err)
+			}
+		}
+		return fn(item)
+	})
+}
+
+// Reads all rows of CSV with header as structs
+func ReadAll[T any](r io.Reader) ([]T, error) {
+	result := make([]T, 0)
+	err := Read(r, func(item T) error {
+		result = append(result, item)
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,167 @@
+package csvutil
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+)
+
+type writer struct {
+	w          *csv.Writer
+	columns    []string
+	formatters map[string]func(v any) string
+	noHeader   bool
+	started    bool
+}
+
+// Option of writer
+type Option func(*writer)
+
+// Streaming CSV writer. Header is written before the first row. Not safe for concurrent use
+type Writer interface {
+	// Writes values of columns of the row. Absent and nil values are empty.
+	// Returns error of value failed to marshal as text.
+	// Signature matches qb EachRow callback
+	//
+	// Ex.: err := qb.New(db).Select("orders").EachRow(w.WriteRow)
+	WriteRow(row jvm.M) error
+	// Writes struct or pointer to struct with fields named by csv tags
+	WriteStruct(v any) error
+	// Writes buffered data
+	Flush() error
+}
+
+// Writer constructor. If columns are not given, they are taken from the first row:
+// sorted keys of map or fields of struct
+//
+// Ex.: w := csvutil.NewWriter(resp, []string{"id", "total"}, csvutil.WithFormatter("total", money)); defer w.Flush()
+func NewWriter(w io.Writer, columns []string, opts ...Option) Writer {
+	cw := &writer{
+		w:          csv.NewWriter(w),
+		columns:    columns,
+		formatters: make(map[string]func(v any) string),
+	}
+	for _, opt := range opts {
+		opt(cw)
+	}
+	return cw
+}
+
+// Formats values of column by fn instead of default formatting
+func WithFormatter(column string, fn func(v any) string) Option {
+	return func(w *writer) {
+		w.formatters[column] = fn
+	}
+}
+
+// Field delimiter instead of comma
+func WithComma(comma rune) Option {
+	return func(w *writer) {
+		w.w.Comma = comma
+	}
+}
+
+// Disables header
+func WithoutHeader() Option {
+	return func(w *writer) {
+		w.noHeader = true
+	}
+}
+
+// Writes rows as CSV with header
+//
+// Ex.: csvutil.WriteMaps(w, rows, "id", "name")
+func WriteMaps(w io.Writer, rows []jvm.M, columns ...string) error {
+	cw := NewWriter(w, columns)
+	for _, row := range rows {
+		if err := cw.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return cw.Flush()
+}
+
+// Writes structs as CSV with header
+//
+// Ex.: csvutil.Write(w, users)
+func Write[T any](w io.Writer, items []T, columns ...string) error {
+	cw := NewWriter(w, columns)
+	for _, item := range items {
+		if err := cw.WriteStruct(item); err != nil {
+			return err
+		}
+	}
+	return cw.Flush()
+}
+
+func (w *writer) WriteRow(row jvm.M) error {
+	if w.columns == nil {
+		w.columns = row.Keys()
+		slices.Sort(w.columns)
+	}
+	record := make([]string, len(w.columns))
+	for i, column := range w.columns {
+		if f, ok := w.formatters[column]; ok {
+			record[i] = f(row[column])
+			continue
+		}
+		s, err := format(row[column])
+		if err != nil {
+			return fmt.Errorf("%w: column %q: %v", jve.ErrBadFormat, column, err)
+		}
+		record[i] = s
+	}
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+	return w.w.Write(record)
+}
+
+func (w *writer) WriteStruct(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return fmt.Errorf("%w: nil pointer", jve.ErrBadType)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T is not a struct", jve.ErrBadType, v)
+	}
+	fields := structFields(rv.Type())
+	row := make(jvm.M, len(fields))
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		row[f.name] = rv.Field(f.index).Interface()
+		names[i] = f.name
+	}
+	if w.columns == nil {
+		w.columns = names
+	}
+	return w.WriteRow(row)
+}
+
+func (w *writer) Flush() error {
+	if !w.started && w.columns != nil {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *writer) start() error {
+	w.started = true
+	if w.noHeader {
+		return nil
+	}
+	return w.w.Write(w.columns)
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+)
+
+// Machine-readable code of error
+type ErrorCode string
+
+const (
+	CodeNotFound      ErrorCode = "not_found"
+	CodeConflict      ErrorCode = "conflict"
+	CodeInvalid       ErrorCode = "invalid"
+	CodeInternal      ErrorCode = "internal"
+	CodeUnauthorized  ErrorCode = "unauthorized"
+	CodeAlreadyExists ErrorCode = "already_exists"
+)
+
+// Codes of sentinel errors
+var sentinelCodes = []struct {
+	err  error
+	code ErrorCode
+}{
+	{ErrPanic, CodeInternal},
+	{ErrNotFound, CodeNotFound},
+	{ErrAlreadyExists, CodeAlreadyExists},
+	{ErrConflict, CodeConflict},
+	{ErrInvalid, CodeInvalid},
+	{ErrBadType, CodeInvalid},
+	{ErrBadFormat, CodeInvalid},
+	{ErrTooManyArgs, CodeInvalid},
+	{ErrUnknownAction, CodeInvalid},
+	{ErrUnauthorized, CodeUnauthorized},
+	{ErrInternal, CodeInternal},
+}
+
+// Error with machine-readable code
+type E struct {
+	Code    ErrorCode
+	Message string
+	Details jvm.M
+	Err     error
+	// Key of message catalog and its arguments used by Localize
+	Key  string
+	Args []any
+}
+
+// Coded error constructor
+//
+// Ex.: errors.New("user not found", errors.CodeNotFound)
+func New(message string, code ErrorCode) error {
+	return &E{
+		Code:    code,
+		Message: message,
+	}
+}
+
+// Returns code of the outermost coded error in the chain.
+// Sentinel errors of the package have their own codes, other errors are internal.
+// Returns empty code for nil error
+func Code(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+	var e *E
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	for _, s := range sentinelCodes {
+		if errors.Is(err, s.err) {
+			return s.code
+		}
+	}
+	return CodeInternal
+}
+
+func (e *E) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *E) Unwrap() error {
+	return e.Err
+}
+
+// Formats error. %+v prints stack trace after the message
+func (e *E) Format(s fmt.State, verb rune) {
+	format(e, s, verb)
+}
+
+// Returns error satisfying errors.Is(err, ErrNotFound) with formatted detail
+//
+// Ex.: errors.NotFoundf("user %d", id)
+func NotFoundf(format string, args ...any) error {
+	return sentinelf(ErrNotFound, CodeNotFound, format, args)
+}
+
+// Returns error satisfying errors.Is(err, ErrAlreadyExists) with formatted detail
+func AlreadyExistsf(format string, args ...any) error {
+	return sentinelf(ErrAlreadyExists, CodeAlreadyExists, format, args)
+}
+
+// Returns error satisfying errors.Is(err, ErrConflict) with formatted detail
+func Conflictf(format string, args ...any) error {
+	return sentinelf(ErrConflict, CodeConflict, format, args)
+}
+
+// Returns error satisfying errors.Is(err, ErrInvalid) with formatted detail
+func Invalidf(format string, args ...any) error {
+	return sentinelf(ErrInvalid, CodeInvalid, format, args)
+}
+
+// Returns error satisfying errors.Is(err, ErrUnauthorized) with formatted detail
+func Unauthorizedf(format string, args ...any) error {
+	return sentinelf(ErrUnauthorized, CodeUnauthorized, format, args)
+}
+
+// Returns error satisfying errors.Is(err, ErrInternal) with formatted detail
+func Internalf(format string, args ...any) error {
+	return sentinelf(ErrInternal, CodeInternal, format, args)
+}
+
+func sentinelf(sentinel error, code ErrorCode, format string, args []any) error {
+	return &E{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+		Err:     sentinel,
+	}
+}
@@ -1,10 +1,22 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+)
 
 var (
-	ErrBadType       = errors.New("bad type error")
+	ErrBadType       = jvm.ErrBadType
 	ErrNotFound      = errors.New("not found")
 	ErrUnknownAction = errors.New("unknown action")
 	ErrTooManyArgs   = errors.New("too many arguments")
+	ErrBadFormat     = errors.New("bad format")
+	ErrConflict      = errors.New("conflict")
+	ErrInvalid       = errors.New("invalid")
+	ErrInternal      = errors.New("internal error")
+	ErrUnauthorized  = errors.New("unauthorized")
+	ErrAlreadyExists = errors.New("already exists")
+	ErrRetryable     = errors.New("retryable")
+	ErrPanic         = errors.New("panic")
 )
@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"fmt"
+
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+)
+
+type withFields struct {
+	err    error
+	fields jvm.M
+}
+
+// Attaches structured context to error. Message of the error is kept.
+// Returns nil if err is nil
+//
+// Ex.: return errors.WithFields(err, jvm.M{"user_id": 7, "table": "orders"})
+func WithFields(err error, fields jvm.M) error {
+	if err == nil {
+		return nil
+	}
+	return &withFields{
+		err:    err,
+		fields: fields.Copy(),
+	}
+}
+
+// Returns fields attached along the error chain. Outer fields take precedence
+//
+// Ex.: log.Printf("%v %s", err, errors.Fields(err).AsString())
+func Fields(err error) jvm.M {
+	result := jvm.New()
+	walk(err, func(err error) {
+		if f, ok := err.(*withFields); ok {
+			for k, v := range f.fields {
+				if _, exists := result[k]; !exists {
+					result[k] = v
+				}
+			}
+		}
+	})
+	return result
+}
+
+func (f *withFields) Error() string {
+	return f.err.Error()
+}
+
+func (f *withFields) Unwrap() error {
+	return f.err
+}
+
+// Formats error. %+v prints stack trace after the message
+func (f *withFields) Format(s fmt.State, verb rune) {
+	format(f, s, verb)
+}
@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Collector of errors. Safe for concurrent use, zero value is ready to use
+//
+// Ex.: var g errors.Group; g.Add(validateName(), validateEmail()); return g.Err()
+type Group struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// Several errors as one error. Is and As match any of errors
+type MultiError struct {
+	errs []error
+}
+
+// Adds errors to group. Nil errors are skipped
+func (g *Group) Add(errs ...error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, err := range errs {
+		if err != nil {
+			g.errs = append(g.errs, err)
+		}
+	}
+}
+
+// Count of errors in group
+func (g *Group) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.errs)
+}
+
+// Returns copy of errors in group
+func (g *Group) Errors() []error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]error(nil), g.errs...)
+}
+
+// Returns errors of group as MultiError or nil if group is empty
+func (g *Group) Err() error {
+	return Join(g.Errors()...)
+}
+
+// Joins errors into MultiError. Nil errors are skipped, returns nil if there are no errors
+func Join(errs ...error) error {
+	result := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			result = append(result, err)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return &MultiError{errs: result}
+}
+
+// Returns joined errors
+func (m *MultiError) Errors() []error {
+	return append([]error(nil), m.errs...)
+}
+
+// Formats errors as numbered list
+func (m *MultiError) Error() string {
+	return m.list("%v")
+}
+
+func (m *MultiError) Unwrap() []error {
+	return m.errs
+}
+
+// Formats error. %+v prints stack traces of errors
+func (m *MultiError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		io.WriteString(s, m.list("%+v"))
+		return
+	}
+	format(m, s, verb)
+}
+
+func (m *MultiError) list(verb string) string {
+	var b strings.Builder
+	if len(m.errs) == 1 {
+		b.WriteString("1 error occurred:")
+	} else {
+		b.WriteString(strconv.Itoa(len(m.errs)) + " errors occurred:")
+	}
+	for i, err := range m.errs {
+		b.WriteString("\n" + strconv.Itoa(i+1) + ". ")
+		b.WriteString(strings.ReplaceAll(fmt.Sprintf(verb, err), "\n", "\n\t"))
+	}
+	return b.String()
+}
@@ -0,0 +1,153 @@
+package grpcerr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain of error info in status details
+const Domain = "jv-go-utils"
+
+var toCodes = map[jve.ErrorCode]codes.Code{
+	jve.CodeNotFound:      codes.NotFound,
+	jve.CodeAlreadyExists: codes.AlreadyExists,
+	jve.CodeConflict:      codes.Aborted,
+	jve.CodeInvalid:       codes.InvalidArgument,
+	jve.CodeUnauthorized:  codes.Unauthenticated,
+	jve.CodeInternal:      codes.Internal,
+}
+
+var fromCodes = map[codes.Code]jve.ErrorCode{
+	codes.NotFound:           jve.CodeNotFound,
+	codes.AlreadyExists:      jve.CodeAlreadyExists,
+	codes.Aborted:            jve.CodeConflict,
+	codes.FailedPrecondition: jve.CodeConflict,
+	codes.InvalidArgument:    jve.CodeInvalid,
+	codes.OutOfRange:         jve.CodeInvalid,
+	codes.Unauthenticated:    jve.CodeUnauthorized,
+	codes.PermissionDenied:   jve.CodeUnauthorized,
+}
+
+var sentinels = map[jve.ErrorCode]error{
+	jve.CodeNotFound:      jve.ErrNotFound,
+	jve.CodeAlreadyExists: jve.ErrAlreadyExists,
+	jve.CodeConflict:      jve.ErrConflict,
+	jve.CodeInvalid:       jve.ErrInvalid,
+	jve.CodeUnauthorized:  jve.ErrUnauthorized,
+	jve.CodeInternal:      jve.ErrInternal,
+}
+
+// Converts error into gRPC status error. Code and details of the error are
+// passed as ErrorInfo in status details with JSON encoded values, message is rendered as errors.ToPublicJSON.
+// Retryable internal errors become Unavailable. Returns nil if err is nil
+//
+// Ex.: return nil, grpcerr.ToGRPC(err)
+func ToGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	rendered := jve.ToPublicJSON(err)
+	code := jve.Code(err)
+	grpcCode, ok := toCodes[code]
+	if !ok {
+		grpcCode = codes.Unknown
+	}
+	if grpcCode == codes.Internal && jve.IsRetryable(err) {
+		grpcCode = codes.Unavailable
+	}
+	st := status.New(grpcCode, rendered["message"].(string))
+
+	info := &errdetails.ErrorInfo{
+		Reason:   string(code),
+		Domain:   Domain,
+		Metadata: make(map[string]string),
+	}
+	for k, v := range rendered["details"].(jvm.M) {
+		b, err := json.Marshal(v)
+		if err != nil {
+			b, _ = json.Marshal(fmt.Sprint(v))
+		}
+		info.Metadata[k] = string(b)
+	}
+	if withDetails, detailsErr := st.WithDetails(info); detailsErr == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// Converts gRPC status error into coded error. Code and details are restored from
+// ErrorInfo of status details or mapped from status code. Result matches sentinel
+// error of its code with errors.Is. Unavailable errors are retryable.
+// Returns err as is if it is not a status error
+func FromGRPC(err error) error {
+	st, ok := status.FromError(err)
+	if !ok || err == nil {
+		return err
+	}
+	switch st.Code() {
+	case codes.OK:
+		return nil
+	case codes.Canceled:
+		return fmt.Errorf("%w: %s", context.Canceled, st.Message())
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("%w: %s", context.DeadlineExceeded, st.Message())
+	}
+
+	code, ok := fromCodes[st.Code()]
+	if !ok {
+		code = jve.CodeInternal
+	}
+	details := jvm.New()
+	for _, d := range st.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || info.Domain != Domain {
+			continue
+		}
+		if info.Reason != "" {
+			code = jve.ErrorCode(info.Reason)
+		}
+		for k, v := range info.Metadata {
+			details[k] = decodeMetadata(v)
+		}
+	}
+
+	var result error = &jve.E{
+		Code:    code,
+		Message: st.Message(),
+		Details: details,
+	}
+	if sentinel, ok := sentinels[code]; ok {
+		result = jve.Mark(result, sentinel)
+	}
+	if st.Code() == codes.Unavailable {
+		result = jve.Retryable(result)
+	}
+	return result
+}
+
+// Decodes JSON value of metadata. Value which is not JSON is kept as string
+func decodeMetadata(s string) any {
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	return v
+}
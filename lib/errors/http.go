@@ -0,0 +1,27 @@
+package errors
+
+import "net/http"
+
+// HTTP statuses of error codes
+var httpStatuses = map[ErrorCode]int{
+	CodeNotFound:      http.StatusNotFound,
+	CodeConflict:      http.StatusConflict,
+	CodeInvalid:       http.StatusBadRequest,
+	CodeInternal:      http.StatusInternalServerError,
+	CodeUnauthorized:  http.StatusUnauthorized,
+	CodeAlreadyExists: http.StatusConflict,
+}
+
+// Returns HTTP status for code of error. Unknown codes are internal server errors.
+// Returns 200 for nil error
+//
+// Ex.: w.WriteHeader(errors.HTTPStatus(err))
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	if status, ok := httpStatuses[Code(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,45 @@
+package errors
+
+import "errors"
+
+// Returns the innermost error of the chain. For joined errors the first one is followed
+func RootCause(err error) error {
+	for err != nil {
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			next := x.Unwrap()
+			if next == nil {
+				return err
+			}
+			err = next
+		case interface{ Unwrap() []error }:
+			errs := x.Unwrap()
+			if len(errs) == 0 {
+				return err
+			}
+			err = errs[0]
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns all errors of the tree in depth-first order, including err itself.
+// Both wrapped and joined errors are flattened
+func All(err error) []error {
+	result := make([]error, 0)
+	walk(err, func(err error) {
+		result = append(result, err)
+	})
+	return result
+}
+
+// Finds the first error of type T in the tree. Generic form of errors.As
+//
+// Ex.: if pqErr, ok := errors.HasType[*pq.Error](err); ok { ... }
+func HasType[T error](err error) (T, bool) {
+	var target T
+	ok := errors.As(err, &target)
+	return target, ok
+}
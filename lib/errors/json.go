@@ -0,0 +1,139 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+)
+
+// Renders error as map with code, full message and details of all coded errors in the chain.
+// Returns nil if err is nil
+//
+// Ex.: {"code": "invalid", "message": "invalid: email: required", "details": {"fields": {"email": ["required"]}}}
+func ToJSON(err error) jvm.M {
+	if err == nil {
+		return nil
+	}
+	return jvm.M{
+		"code":    Code(err),
+		"message": err.Error(),
+		"details": details(err),
+	}
+}
+
+// Renders error as ToJSON, but hides messages of wrapped errors and internal errors,
+// so result may be sent to clients.
+// Message and details of the outermost coded error or sentinel error are used.
+//
+// Ex.: {"code": "internal", "message": "internal error", "details": {}}
+func ToPublicJSON(err error) jvm.M {
+	if err == nil {
+		return nil
+	}
+	code := Code(err)
+	if code == CodeInternal {
+		return jvm.M{
+			"code":    code,
+			"message": ErrInternal.Error(),
+			"details": jvm.New(),
+		}
+	}
+	return jvm.M{
+		"code":    code,
+		"message": publicMessage(err),
+		"details": publicDetails(err),
+	}
+}
+
+// Marshals error as ToJSON
+func (e *E) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ToJSON(e))
+}
+
+// Merges details of coded errors in the chain. Outer errors take precedence.
+// Messages of validation errors are added as fields
+func details(err error) jvm.M {
+	result := jvm.New()
+	walk(err, func(err error) {
+		switch e := err.(type) {
+		case *E:
+			for k, v := range e.Details {
+				if _, exists := result[k]; !exists {
+					result[k] = v
+				}
+			}
+		case *Validation:
+			if _, exists := result["fields"]; !exists {
+				result["fields"] = e.ToMap()
+			}
+		}
+	})
+	return result
+}
+
+// Returns details of the outermost coded or validation error only,
+// so details of wrapped errors are not exposed
+func publicDetails(err error) jvm.M {
+	result := jvm.New()
+	found := false
+	walk(err, func(err error) {
+		if found {
+			return
+		}
+		switch e := err.(type) {
+		case *E:
+			found = true
+			for k, v := range e.Details {
+				result[k] = v
+			}
+		case *Validation:
+			found = true
+			result["fields"] = e.ToMap()
+		}
+	})
+	return result
+}
+
+func publicMessage(err error) string {
+	var e *E
+	if errors.As(err, &e) {
+		if isSentinel(e.Err) {
+			return e.Message + ": " + e.Err.Error()
+		}
+		return e.Message
+	}
+	for _, s := range sentinelCodes {
+		if errors.Is(err, s.err) {
+			return s.err.Error()
+		}
+	}
+	return ErrInternal.Error()
+}
+
+func isSentinel(err error) bool {
+	for _, s := range sentinelCodes {
+		if err == s.err {
+			return true
+		}
+	}
+	return false
+}
+
+// Calls fn for every error of the tree in depth-first order, including joined errors
+func walk(err error, fn func(error)) {
+	for err != nil {
+		fn(err)
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			err = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, e := range x.Unwrap() {
+				walk(e, fn)
+			}
+			return
+		default:
+			return
+		}
+	}
+}
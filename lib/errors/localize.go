@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var catalog = struct {
+	sync.RWMutex
+	messages map[string]map[string]string
+}{
+	messages: make(map[string]map[string]string),
+}
+
+// Coded error constructor with key of message catalog. Internal message is formatted
+// with the same arguments as translations
+//
+// Ex.: errors.NewLocalized(errors.CodeNotFound, "user.not_found", "user %d not found", id)
+func NewLocalized(code ErrorCode, key string, message string, args ...any) error {
+	return &E{
+		Code:    code,
+		Message: fmt.Sprintf(message, args...),
+		Key:     key,
+		Args:    args,
+	}
+}
+
+// Registers translations of the language. Translations are fmt format strings,
+// explicit argument indexes like %[2]s may be used to reorder arguments.
+// Codes may be used as keys to translate errors without key
+//
+// Ex.: errors.RegisterMessages("de", map[string]string{"user.not_found": "Benutzer %d nicht gefunden"})
+func RegisterMessages(lang string, messages map[string]string) {
+	catalog.Lock()
+	defer catalog.Unlock()
+	lang = strings.ToLower(lang)
+	if catalog.messages[lang] == nil {
+		catalog.messages[lang] = make(map[string]string, len(messages))
+	}
+	for key, message := range messages {
+		catalog.messages[lang][key] = message
+	}
+}
+
+// Renders user-facing message of error in the language.
+// Key of the outermost coded error is used, then code of error.
+// Region is dropped if there is no translation for it, e.g. "en-US" falls back to "en".
+// Public message of the error is returned if there is no translation.
+// Returns empty string if err is nil
+func Localize(err error, lang string) string {
+	if err == nil {
+		return ""
+	}
+	var e *E
+	if errors.As(err, &e) && e.Key != "" {
+		if template, ok := translation(lang, e.Key); ok {
+			return fmt.Sprintf(template, e.Args...)
+		}
+	}
+	if template, ok := translation(lang, string(Code(err))); ok {
+		return fmt.Sprintf(template)
+	}
+	return publicMessage(err)
+}
+
+func translation(lang string, key string) (string, bool) {
+	catalog.RLock()
+	defer catalog.RUnlock()
+	lang = strings.ToLower(lang)
+	for {
+		if template, ok := catalog.messages[lang][key]; ok {
+			return template, true
+		}
+		i := strings.LastIndexAny(lang, "-_")
+		if i < 0 {
+			return "", false
+		}
+		lang = lang[:i]
+	}
+}
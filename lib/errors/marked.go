@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error marked with sentinel errors. Message of the original error is kept
+type marked struct {
+	err   error
+	marks []error
+}
+
+// Marks error as retryable. Returns nil if err is nil
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return Mark(err, ErrRetryable)
+}
+
+// Checks whether operation failed with error may be retried
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrRetryable)
+}
+
+// Marks error with sentinel errors, so errors.Is matches them.
+// Message of the error is kept. Returns nil if err is nil
+//
+// Ex.: errors.Mark(err, errors.ErrNotFound)
+func Mark(err error, marks ...error) error {
+	if err == nil {
+		return nil
+	}
+	return &marked{
+		err:   err,
+		marks: marks,
+	}
+}
+
+func (m *marked) Error() string {
+	return m.err.Error()
+}
+
+func (m *marked) Unwrap() []error {
+	return append([]error{m.err}, m.marks...)
+}
+
+// Formats error. %+v prints stack trace after the message
+func (m *marked) Format(s fmt.State, verb rune) {
+	format(m, s, verb)
+}
@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"log"
+	"sync/atomic"
+)
+
+var ignoreHook atomic.Pointer[func(error)]
+
+// Returns value or panics with error. Intended for initialization code
+//
+// Ex.: tmpl := errors.Must(template.ParseFiles("index.html"))
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Returns value and passes error to the ignore hook
+//
+// Ex.: port := errors.Ignore(strconv.Atoi(s))
+func Ignore[T any](v T, err error) T {
+	if err != nil {
+		if hook := ignoreHook.Load(); hook != nil {
+			(*hook)(err)
+		} else {
+			log.Printf("ignored error: %v", err)
+		}
+	}
+	return v
+}
+
+// Sets hook for errors discarded by Ignore. Nil restores default hook which logs
+// errors with the standard logger
+func SetIgnoreHook(hook func(err error)) {
+	if hook == nil {
+		ignoreHook.Store(nil)
+		return
+	}
+	ignoreHook.Store(&hook)
+}
@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// Error of PostgreSQL driver. Implemented by lib/pq and pgx errors
+type sqlStater interface {
+	SQLState() string
+}
+
+// Sentinel errors of SQLSTATE codes
+var pgStates = map[string][]error{
+	"23505": {ErrAlreadyExists},          // unique_violation
+	"23503": {ErrConflict},               // foreign_key_violation
+	"23P01": {ErrConflict},               // exclusion_violation
+	"23502": {ErrInvalid},                // not_null_violation
+	"23514": {ErrInvalid},                // check_violation
+	"22001": {ErrInvalid},                // string_data_right_truncation
+	"22003": {ErrInvalid},                // numeric_value_out_of_range
+	"22007": {ErrInvalid},                // invalid_datetime_format
+	"22P02": {ErrInvalid},                // invalid_text_representation
+	"40001": {ErrConflict, ErrRetryable}, // serialization_failure
+	"40P01": {ErrConflict, ErrRetryable}, // deadlock_detected
+	"55P03": {ErrConflict, ErrRetryable}, // lock_not_available
+	"28000": {ErrUnauthorized},           // invalid_authorization_specification
+	"28P01": {ErrUnauthorized},           // invalid_password
+	"42501": {ErrUnauthorized},           // insufficient_privilege
+	"53300": {ErrRetryable},              // too_many_connections
+	"57P01": {ErrRetryable},              // admin_shutdown
+}
+
+// Translates errors of PostgreSQL drivers into sentinel errors of the package.
+// Original error is kept in the chain, so errors.As to driver error type still works.
+// sql.ErrNoRows is translated into ErrNotFound, connection exceptions are retryable.
+// Returns nil if err is nil
+//
+// Ex.: if errors.Is(errors.FromPG(err), errors.ErrAlreadyExists) { ... }
+func FromPG(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return Mark(err, ErrNotFound)
+	}
+	var pgErr sqlStater
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	state := pgErr.SQLState()
+	if marks, ok := pgStates[state]; ok {
+		return Mark(err, marks...)
+	}
+	// class 08 - connection exception
+	if strings.HasPrefix(state, "08") {
+		return Mark(err, ErrRetryable)
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package errors
+
+import "fmt"
+
+type panicError struct {
+	value any
+	stack []uintptr
+}
+
+// Converts panic into error with stack trace of the panic. Must be deferred directly.
+// Error satisfies errors.Is(err, ErrPanic) and wraps the panic value if it is an error
+//
+// Ex.: defer errors.Recover(&err)
+func Recover(err *error) {
+	if r := recover(); r != nil {
+		*err = &panicError{
+			value: r,
+			// skip runtime.Callers, callers, Recover and runtime.gopanic
+			stack: callers(4),
+		}
+	}
+}
+
+// Calls function converting its panic into error
+//
+// Ex.: err := errors.Safe(func() error { return callback(item) })
+func Safe(fn func() error) (err error) {
+	defer Recover(&err)
+	return fn()
+}
+
+func (p *panicError) Error() string {
+	return ErrPanic.Error() + ": " + fmt.Sprint(p.value)
+}
+
+func (p *panicError) Unwrap() []error {
+	if err, ok := p.value.(error); ok {
+		return []error{err, ErrPanic}
+	}
+	return []error{ErrPanic}
+}
+
+func (p *panicError) stackTrace() []uintptr {
+	return p.stack
+}
+
+// Formats error. %+v prints stack trace of the panic after the message
+func (p *panicError) Format(s fmt.State, verb rune) {
+	format(p, s, verb)
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+)
+
+const maxStackDepth = 32
+
+type stackTracer interface {
+	stackTrace() []uintptr
+}
+
+type wrapped struct {
+	msg   string
+	err   error
+	stack []uintptr
+}
+
+// Wraps error with message. Stack trace is captured if the error doesn't have it yet.
+// Returns nil if err is nil
+//
+// Ex.: return errors.Wrap(err, "load user")
+func Wrap(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return wrap(err, msg)
+}
+
+// Wraps error with formatted message. Stack trace is captured if the error doesn't have it yet.
+// Returns nil if err is nil
+//
+// Ex.: return errors.Wrapf(err, "load user %d", id)
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return wrap(err, fmt.Sprintf(format, args...))
+}
+
+// Returns the stack trace captured closest to the origin of error or nil if there is none.
+// Joined and marked errors are searched in the same order as errors.As does
+func StackTrace(err error) []runtime.Frame {
+	origin := originStack(err)
+	if origin == nil {
+		return nil
+	}
+	result := make([]runtime.Frame, 0, len(origin))
+	frames := runtime.CallersFrames(origin)
+	for {
+		frame, more := frames.Next()
+		result = append(result, frame)
+		if !more {
+			break
+		}
+	}
+	return result
+}
+
+// Returns the deepest stack of the first branch of error tree having one
+func originStack(err error) []uintptr {
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		if inner := originStack(x.Unwrap()); inner != nil {
+			return inner
+		}
+	case interface{ Unwrap() []error }:
+		for _, e := range x.Unwrap() {
+			if inner := originStack(e); inner != nil {
+				return inner
+			}
+		}
+	}
+	if st, ok := err.(stackTracer); ok {
+		return st.stackTrace()
+	}
+	return nil
+}
+
+func wrap(err error, msg string) *wrapped {
+	w := &wrapped{
+		msg: msg,
+		err: err,
+	}
+	var st stackTracer
+	if !errors.As(err, &st) {
+		w.stack = callers(4)
+	}
+	return w
+}
+
+func (w *wrapped) Error() string {
+	return w.msg + ": " + w.err.Error()
+}
+
+func (w *wrapped) Unwrap() error {
+	return w.err
+}
+
+func (w *wrapped) stackTrace() []uintptr {
+	return w.stack
+}
+
+// Formats error. %+v prints stack trace after the message
+func (w *wrapped) Format(s fmt.State, verb rune) {
+	format(w, s, verb)
+}
+
+// Captures stack trace skipping the given count of frames
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip, pcs)
+	return pcs[:n]
+}
+
+func format(err error, s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		io.WriteString(s, err.Error())
+		if s.Flag('+') {
+			for _, frame := range StackTrace(err) {
+				io.WriteString(s, "\n"+frame.Function+"\n\t"+frame.File+":"+strconv.Itoa(frame.Line))
+			}
+		}
+	case 's':
+		io.WriteString(s, err.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", err.Error())
+	}
+}
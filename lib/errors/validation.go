@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+)
+
+// Validation error with messages per field. Satisfies errors.Is(err, ErrInvalid).
+// Not safe for concurrent use
+//
+// Ex.: v := errors.NewValidation(); v.Add("email", "invalid format"); return v.Err()
+type Validation struct {
+	fields map[string][]string
+	order  []string
+}
+
+// Validation error constructor
+func NewValidation() *Validation {
+	return &Validation{
+		fields: make(map[string][]string),
+	}
+}
+
+// Adds message for the field. Zero Validation is ready to use
+func (v *Validation) Add(field string, message string) *Validation {
+	if v.fields == nil {
+		v.fields = make(map[string][]string)
+	}
+	if _, ok := v.fields[field]; !ok {
+		v.order = append(v.order, field)
+	}
+	v.fields[field] = append(v.fields[field], message)
+	return v
+}
+
+// Adds formatted message for the field
+func (v *Validation) Addf(field string, format string, args ...any) *Validation {
+	return v.Add(field, fmt.Sprintf(format, args...))
+}
+
+// Adds messages of another validation error
+func (v *Validation) Merge(other *Validation) *Validation {
+	if other == nil {
+		return v
+	}
+	for _, field := range other.order {
+		for _, message := range other.fields[field] {
+			v.Add(field, message)
+		}
+	}
+	return v
+}
+
+// Count of invalid fields
+func (v *Validation) Len() int {
+	return len(v.order)
+}
+
+// Returns messages of the field
+func (v *Validation) Messages(field string) []string {
+	return v.fields[field]
+}
+
+// Returns validation error or nil if there are no messages
+func (v *Validation) Err() error {
+	if v == nil || len(v.order) == 0 {
+		return nil
+	}
+	return v
+}
+
+func (v *Validation) Error() string {
+	items := make([]string, len(v.order))
+	for i, field := range v.order {
+		items[i] = field + ": " + strings.Join(v.fields[field], ", ")
+	}
+	return ErrInvalid.Error() + ": " + strings.Join(items, "; ")
+}
+
+func (v *Validation) Is(target error) bool {
+	return target == ErrInvalid
+}
+
+// Returns messages per field. Implements maps.Mapping
+//
+// Ex.: {"email": ["invalid format"]}
+func (v *Validation) ToMap(fields ...string) jvm.M {
+	if len(fields) == 0 {
+		fields = v.order
+	}
+	result := make(jvm.M, len(fields))
+	for _, field := range fields {
+		if messages, ok := v.fields[field]; ok {
+			result[field] = append([]string(nil), messages...)
+		}
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+package flight
+
+import (
+	"sync"
+	"time"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+)
+
+type call[V any] struct {
+	done      chan struct{}
+	value     V
+	err       error
+	dups      int
+	finished  bool
+	expiresAt time.Time
+}
+
+type config struct {
+	ttl time.Duration
+}
+
+type group[K comparable, V any] struct {
+	config
+	mu        sync.Mutex
+	calls     map[K]*call[V]
+	lastSweep time.Time
+}
+
+// Option of group
+type Option func(*config)
+
+// Deduplicator of concurrent calls with the same key. Safe for concurrent use
+type Group[K comparable, V any] interface {
+	// Executes fn once for concurrent calls with the same key and returns its result to all of them.
+	// Shared is true if result was given to several callers or reused.
+	// Panic of fn is returned as error
+	//
+	// Ex.: user, err, _ := g.Do(id, func() (jvm.M, error) { return qb.New(db).Select("users").Where("id=?", id).Row() })
+	Do(key K, fn func() (V, error)) (v V, err error, shared bool)
+	// Forgets key, so the next call executes fn even if another call is in flight or result is reused
+	Forget(key K)
+}
+
+// Group constructor
+//
+// Ex.: g := flight.New[int, jvm.M](flight.WithTTL(time.Second))
+func New[K comparable, V any](opts ...Option) Group[K, V] {
+	g := &group[K, V]{
+		calls: make(map[K]*call[V]),
+	}
+	for _, opt := range opts {
+		opt(&g.config)
+	}
+	return g
+}
+
+// Reuses successful result for ttl after call finishes. Errors are never reused
+func WithTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+func (g *group[K, V]) Do(key K, fn func() (V, error)) (V, error, bool) {
+	now := time.Now()
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		if !c.finished {
+			c.dups++
+			g.mu.Unlock()
+			<-c.done
+			return c.value, c.err, true
+		}
+		if now.Before(c.expiresAt) {
+			g.mu.Unlock()
+			return c.value, nil, true
+		}
+		delete(g.calls, key)
+	}
+	g.sweep(now)
+	c := &call[V]{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.err = jve.Safe(func() error {
+		var err error
+		c.value, err = fn()
+		return err
+	})
+
+	g.mu.Lock()
+	c.finished = true
+	if c.err == nil && g.ttl > 0 {
+		c.expiresAt = time.Now().Add(g.ttl)
+	} else if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+	shared := c.dups > 0
+	g.mu.Unlock()
+	close(c.done)
+	return c.value, c.err, shared
+}
+
+func (g *group[K, V]) Forget(key K) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.calls, key)
+}
+
+// Removes expired results at most once per ttl. Must be called with locked mutex
+func (g *group[K, V]) sweep(now time.Time) {
+	if g.ttl <= 0 || now.Sub(g.lastSweep) < g.ttl {
+		return
+	}
+	g.lastSweep = now
+	for key, c := range g.calls {
+		if c.finished && !now.Before(c.expiresAt) {
+			delete(g.calls, key)
+		}
+	}
+}
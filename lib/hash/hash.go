@@ -0,0 +1,71 @@
+package hash
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+)
+
+// Compares strings in constant time
+func Equal(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// SHA-256 of data as hex
+func SHA256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// SHA-256 of data as standard base64
+func SHA256Base64(data []byte) string {
+	sum := sha256.Sum256(data)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// HMAC-SHA256 signature of payload as hex
+//
+// Ex.: req.Header.Set("X-Signature", "sha256="+hash.Sign(secret, body))
+func Sign(secret []byte, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// HMAC-SHA256 signature of map encoded as JSON with sorted keys
+func SignM(secret []byte, body jvm.M) (string, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	return Sign(secret, b), nil
+}
+
+// Checks hex HMAC-SHA256 signature of payload in constant time. Prefix "sha256=" is allowed
+//
+// Ex.: hash.Verify(secret, body, r.Header.Get("X-Hub-Signature-256"))
+func Verify(secret []byte, payload []byte, signature string) bool {
+	signature = strings.TrimPrefix(signature, "sha256=")
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// Checks signature of map made by SignM
+func VerifyM(secret []byte, body jvm.M, signature string) bool {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return false
+	}
+	return Verify(secret, b, signature)
+}
@@ -0,0 +1,174 @@
+package hash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Default cost of bcrypt
+const DefaultBcryptCost = 12
+
+// Upper bound of argon2id memory in KiB accepted from stored hash
+const MaxArgon2Memory = 1024 * 1024
+
+// Parameters of argon2id
+type Argon2Params struct {
+	// Memory in KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// Parameters of argon2id recommended by RFC 9106 for memory-constrained environments
+var DefaultArgon2 = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 4,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+type bcryptHasher struct {
+	cost int
+}
+
+type argon2Hasher struct {
+	params Argon2Params
+}
+
+// Password hasher. Hashes of both bcrypt and argon2id are verified by any hasher,
+// so stored hashes may be upgraded to new algorithm or parameters on login
+//
+// Ex.: if ok, _ := h.Verify(password, stored); ok && h.NeedsRehash(stored) { stored, _ = h.Hash(password) }
+type Hasher interface {
+	// Returns encoded hash of password with random salt
+	Hash(password string) (string, error)
+	// Checks password against encoded hash. Returns error for malformed hash
+	Verify(password string, encoded string) (bool, error)
+	// Returns true if hash was made by another algorithm or with other parameters
+	NeedsRehash(encoded string) bool
+}
+
+var defaultHasher = NewArgon2id(DefaultArgon2)
+
+// Bcrypt hasher. Cost less than bcrypt.MinCost is replaced by DefaultBcryptCost
+func NewBcrypt(cost int) Hasher {
+	if cost < bcrypt.MinCost {
+		cost = DefaultBcryptCost
+	}
+	return &bcryptHasher{cost: cost}
+}
+
+// Argon2id hasher
+//
+// Ex.: hash.NewArgon2id(hash.DefaultArgon2)
+func NewArgon2id(params Argon2Params) Hasher {
+	return &argon2Hasher{params: params}
+}
+
+// Hashes password by argon2id with default parameters
+func HashPassword(password string) (string, error) {
+	return defaultHasher.Hash(password)
+}
+
+// Checks password against bcrypt or argon2id hash
+func VerifyPassword(password string, encoded string) (bool, error) {
+	return defaultHasher.Verify(password, encoded)
+}
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	return string(b), err
+}
+
+func (h *bcryptHasher) Verify(password string, encoded string) (bool, error) {
+	return verify(password, encoded)
+}
+
+func (h *bcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != h.cost
+}
+
+func (h *argon2Hasher) Hash(password string) (string, error) {
+	p := h.params
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h *argon2Hasher) Verify(password string, encoded string) (bool, error) {
+	return verify(password, encoded)
+}
+
+func (h *argon2Hasher) NeedsRehash(encoded string) bool {
+	p, salt, key, err := parseArgon2(encoded)
+	if err != nil {
+		return true
+	}
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+	return p != h.params
+}
+
+// Verifies hash of any supported algorithm
+func verify(password string, encoded string) (bool, error) {
+	if strings.HasPrefix(encoded, "$argon2id$") {
+		p, salt, key, err := parseArgon2(encoded)
+		if err != nil {
+			return false, err
+		}
+		other := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, uint32(len(key)))
+		return subtle.ConstantTimeCompare(key, other) == 1, nil
+	}
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return false, nil
+	}
+	return false, fmt.Errorf("%w: bad password hash: %v", jve.ErrBadFormat, err)
+}
+
+func parseArgon2(encoded string) (Argon2Params, []byte, []byte, error) {
+	var p Argon2Params
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, fmt.Errorf("%w: bad argon2id hash", jve.ErrBadFormat)
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, fmt.Errorf("%w: unsupported argon2 version", jve.ErrBadFormat)
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, fmt.Errorf("%w: bad argon2id parameters", jve.ErrBadFormat)
+	}
+	// argon2 panics on zero iterations or parallelism, memory of tampered hash must not exhaust the process
+	if p.Iterations < 1 || p.Parallelism < 1 || p.Memory > MaxArgon2Memory {
+		return p, nil, nil, fmt.Errorf("%w: argon2id parameters out of range", jve.ErrBadFormat)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("%w: bad argon2id salt", jve.ErrBadFormat)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, fmt.Errorf("%w: bad argon2id key", jve.ErrBadFormat)
+	}
+	return p, salt, key, nil
+}
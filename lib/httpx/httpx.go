@@ -0,0 +1,99 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+	"github.com/jvnonce/jv-go-utils/lib/log"
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+	"github.com/jvnonce/jv-go-utils/lib/validate"
+)
+
+// Default limit of request body for DecodeJSON
+const DefaultMaxBodySize int64 = 1 << 20
+
+// Decodes JSON body of request into v with limit of DefaultMaxBodySize and validates it by validate.Value.
+// Malformed body returns error with invalid code
+//
+// Ex.: var m jvm.M; if err := httpx.DecodeJSON(r, &m); err != nil { httpx.Error(w, err); return }
+func DecodeJSON(r *http.Request, v any) error {
+	return DecodeJSONLimit(r, v, DefaultMaxBodySize)
+}
+
+// Decodes JSON body of request into v and validates it by validate.Value. Body longer than maxBytes is rejected
+//
+// Ex.: httpx.DecodeJSONLimit(r, &m, 10<<20)
+func DecodeJSONLimit(r *http.Request, v any, maxBytes int64) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return fmt.Errorf("%w: empty request body", jve.ErrInvalid)
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBytes))
+	if err := dec.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.Is(err, io.EOF):
+			return fmt.Errorf("%w: empty request body", jve.ErrInvalid)
+		case errors.As(err, &tooLarge):
+			return fmt.Errorf("%w: request body is larger than %d bytes", jve.ErrInvalid, maxBytes)
+		}
+		return fmt.Errorf("%w: %v", jve.ErrBadFormat, err)
+	}
+	if dec.More() {
+		return fmt.Errorf("%w: request body must contain a single JSON value", jve.ErrBadFormat)
+	}
+	return validate.Value(v)
+}
+
+// Writes v as JSON response with status
+//
+// Ex.: httpx.WriteJSON(w, http.StatusCreated, jvm.M{"id": id})
+func WriteJSON(w http.ResponseWriter, status int, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// Writes error as JSON response with status of its code. Internal errors are hidden
+// from the client and logged by default logger
+//
+// Ex.: httpx.Error(w, errors.NotFoundf("user %d", id))
+func Error(w http.ResponseWriter, err error) {
+	status := jve.HTTPStatus(err)
+	if status >= http.StatusInternalServerError {
+		log.Error("http request failed", log.Err(err))
+	}
+	WriteJSON(w, status, jve.ToPublicJSON(err))
+}
+
+// Returns query parameters as map. Parameter with one value is string,
+// repeated parameter is []string. If fields are given, other parameters are skipped
+//
+// Ex.: httpx.Query(r, "status", "tag") // ?status=new&tag=a&tag=b -> {"status": "new", "tag": ["a", "b"]}
+func Query(r *http.Request, fields ...string) jvm.M {
+	values := r.URL.Query()
+	result := jvm.New()
+	if len(fields) == 0 {
+		for key := range values {
+			fields = append(fields, key)
+		}
+	}
+	for _, key := range fields {
+		switch v := values[key]; len(v) {
+		case 0:
+		case 1:
+			result[key] = v[0]
+		default:
+			result[key] = v
+		}
+	}
+	return result
+}
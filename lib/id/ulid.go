@@ -0,0 +1,206 @@
+package id
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+)
+
+// Crockford's base32 alphabet
+const ulidAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+const ulidLength = 26
+
+// Lexicographically sortable identifier: 48 bits of milliseconds and 80 random bits.
+// Implements sql.Scanner and driver.Valuer
+type ULID [16]byte
+
+type monotonic struct {
+	mu   sync.Mutex
+	last ULID
+}
+
+// Generator of ULIDs
+type Generator interface {
+	// Returns new ULID
+	New() ULID
+}
+
+var ulidDecoding [256]byte
+
+func init() {
+	for i := range ulidDecoding {
+		ulidDecoding[i] = 0xff
+	}
+	for i := 0; i < len(ulidAlphabet); i++ {
+		c := ulidAlphabet[i]
+		ulidDecoding[c] = byte(i)
+		if c >= 'A' {
+			ulidDecoding[c+'a'-'A'] = byte(i)
+		}
+	}
+}
+
+// ULID with current time and random part
+//
+// Ex.: id.NewULID().String() // "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+func NewULID() ULID {
+	return ULIDAt(time.Now())
+}
+
+// ULID with the given time and random part
+func ULIDAt(t time.Time) ULID {
+	var u ULID
+	u.setTime(t)
+	rand.Read(u[6:])
+	return u
+}
+
+// Generator of strictly increasing ULIDs. ULIDs of the same millisecond
+// increment random part of the previous one, so they keep order of generation.
+// Safe for concurrent use
+//
+// Ex.: gen := id.NewMonotonic(); qb.Insert("events").ColsWithParams(jvm.M{"id": gen.New()})
+func NewMonotonic() Generator {
+	return &monotonic{}
+}
+
+func (m *monotonic) New() ULID {
+	now := NewULID()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if now.Time().After(m.last.Time()) {
+		m.last = now
+		return now
+	}
+	// the same millisecond or clock moved back: increment random part of the last ULID
+	next := m.last
+	for i := 15; i >= 6; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+		if i == 6 {
+			// random part overflowed, move to the next millisecond
+			next.setTime(next.Time().Add(time.Millisecond))
+		}
+	}
+	m.last = next
+	return next
+}
+
+// Parses ULID in canonical form, case-insensitively
+func ParseULID(s string) (ULID, error) {
+	var u ULID
+	if len(s) != ulidLength || s[0] > '7' {
+		return u, fmt.Errorf("%w: bad ulid %q", jve.ErrBadFormat, s)
+	}
+	for i := 0; i < ulidLength; i++ {
+		v := ulidDecoding[s[i]]
+		if v == 0xff {
+			return ULID{}, fmt.Errorf("%w: bad ulid %q", jve.ErrBadFormat, s)
+		}
+		// 26 characters hold 130 bits, the first 2 bits are unused
+		for bit := 0; bit < 5; bit++ {
+			pos := i*5 + bit - 2
+			if pos >= 0 && v&(0x10>>bit) != 0 {
+				u[pos/8] |= 0x80 >> (pos % 8)
+			}
+		}
+	}
+	return u, nil
+}
+
+// Returns true if s is valid ULID
+func IsULID(s string) bool {
+	_, err := ParseULID(s)
+	return err == nil
+}
+
+// Time of ULID with millisecond precision
+func (u ULID) Time() time.Time {
+	ms := int64(binary.BigEndian.Uint16(u[0:2]))<<32 | int64(binary.BigEndian.Uint32(u[2:6]))
+	return time.UnixMilli(ms)
+}
+
+func (u ULID) IsZero() bool {
+	return u == ULID{}
+}
+
+// ULID as UUID with the same bytes, for storing in uuid columns
+func (u ULID) UUID() UUID {
+	return UUID(u)
+}
+
+// Canonical form of ULID
+func (u ULID) String() string {
+	var buf [ulidLength]byte
+	for i := range buf {
+		var v byte
+		for bit := 0; bit < 5; bit++ {
+			pos := i*5 + bit - 2
+			v <<= 1
+			if pos >= 0 && u[pos/8]&(0x80>>(pos%8)) != 0 {
+				v |= 1
+			}
+		}
+		buf[i] = ulidAlphabet[v]
+	}
+	return string(buf[:])
+}
+
+// Value for database
+func (u ULID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// Scanner for ULID. Accepts canonical form, UUID text form and 16 raw bytes. NULL is scanned as zero ULID
+func (u *ULID) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*u = ULID{}
+		return nil
+	case string:
+		return u.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == 16 {
+			copy(u[:], v)
+			return nil
+		}
+		return u.UnmarshalText(v)
+	}
+	return fmt.Errorf("%w: can't scan %T into ulid", jve.ErrBadType, value)
+}
+
+func (u ULID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// Unmarshals canonical form or UUID text form
+func (u *ULID) UnmarshalText(b []byte) error {
+	if len(b) != ulidLength {
+		uuid, err := ParseUUID(string(b))
+		if err != nil {
+			return err
+		}
+		*u = ULID(uuid)
+		return nil
+	}
+	parsed, err := ParseULID(string(b))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+func (u *ULID) setTime(t time.Time) {
+	ms := uint64(t.UnixMilli())
+	binary.BigEndian.PutUint16(u[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(u[2:6], uint32(ms))
+}
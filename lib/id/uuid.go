@@ -0,0 +1,150 @@
+package id
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+)
+
+// UUID of RFC 9562. Implements sql.Scanner and driver.Valuer, so it may be used in qb parameters and results
+type UUID [16]byte
+
+// Zero UUID
+var Nil UUID
+
+// Random UUID version 4
+//
+// Ex.: id.NewV4().String() // "1b4e28ba-2fa1-4d2e-a8c7-0d0f5e1b7c3a"
+func NewV4() UUID {
+	var u UUID
+	rand.Read(u[:])
+	return u.setVersion(4)
+}
+
+// Time-ordered UUID version 7. UUIDs of different milliseconds are sorted by time,
+// order within the same millisecond is random
+//
+// Ex.: qb.Insert("orders").ColsWithParams(jvm.M{"id": id.NewV7(), "total": total})
+func NewV7() UUID {
+	var u UUID
+	rand.Read(u[6:])
+	ms := uint64(time.Now().UnixMilli())
+	binary.BigEndian.PutUint16(u[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(u[2:6], uint32(ms))
+	return u.setVersion(7)
+}
+
+// Parses UUID in canonical form, optionally in braces or with urn:uuid: prefix
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	switch len(s) {
+	case 36:
+	case 38:
+		if s[0] != '{' || s[37] != '}' {
+			return u, fmt.Errorf("%w: bad uuid %q", jve.ErrBadFormat, s)
+		}
+		s = s[1:37]
+	case 45:
+		if s[:9] != "urn:uuid:" {
+			return u, fmt.Errorf("%w: bad uuid %q", jve.ErrBadFormat, s)
+		}
+		s = s[9:]
+	default:
+		return u, fmt.Errorf("%w: bad uuid %q", jve.ErrBadFormat, s)
+	}
+	if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, fmt.Errorf("%w: bad uuid %q", jve.ErrBadFormat, s)
+	}
+	b := []byte(s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:36])
+	if _, err := hex.Decode(u[:], b); err != nil {
+		return Nil, fmt.Errorf("%w: bad uuid %q", jve.ErrBadFormat, s)
+	}
+	return u, nil
+}
+
+// Returns true if s is valid UUID
+func IsUUID(s string) bool {
+	_, err := ParseUUID(s)
+	return err == nil
+}
+
+// Version of UUID
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// Time of UUID version 7. Zero time for other versions
+func (u UUID) Time() time.Time {
+	if u.Version() != 7 {
+		return time.Time{}
+	}
+	ms := int64(binary.BigEndian.Uint16(u[0:2]))<<32 | int64(binary.BigEndian.Uint32(u[2:6]))
+	return time.UnixMilli(ms)
+}
+
+func (u UUID) IsZero() bool {
+	return u == Nil
+}
+
+// Canonical form of UUID
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// Value for database
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// Scanner for UUID. Accepts text form and 16 raw bytes. NULL is scanned as Nil
+func (u *UUID) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*u = Nil
+		return nil
+	case string:
+		return u.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == 16 {
+			copy(u[:], v)
+			return nil
+		}
+		return u.UnmarshalText(v)
+	}
+	return fmt.Errorf("%w: can't scan %T into uuid", jve.ErrBadType, value)
+}
+
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+func (u *UUID) UnmarshalText(b []byte) error {
+	parsed, err := ParseUUID(string(b))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// Sets version and RFC 9562 variant
+func (u UUID) setVersion(version byte) UUID {
+	u[6] = u[6]&0x0f | version<<4
+	u[8] = u[8]&0x3f | 0x80
+	return u
+}
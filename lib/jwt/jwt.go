@@ -0,0 +1,346 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+)
+
+var (
+	// Token is malformed, has bad signature or claims. Satisfies errors.Is(err, errors.ErrUnauthorized)
+	ErrInvalid = fmt.Errorf("%w: invalid token", jve.ErrUnauthorized)
+	// Token is expired
+	ErrExpired = fmt.Errorf("%w: token expired", jve.ErrUnauthorized)
+	// Token is not valid yet
+	ErrNotYetValid = fmt.Errorf("%w: token not valid yet", jve.ErrUnauthorized)
+	// Request has no token
+	ErrNoToken = fmt.Errorf("%w: no token", jve.ErrUnauthorized)
+)
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+type config struct {
+	ttl      time.Duration
+	skew     time.Duration
+	issuer   string
+	audience string
+	cookie   string
+	now      func() time.Time
+}
+
+type jwt struct {
+	config
+	keys KeyProvider
+}
+
+type contextKey struct{}
+
+// Option of issuer and verifier
+type Option func(*config)
+
+// Issues and verifies tokens with claims as map
+type JWT interface {
+	// Signs claims by signing key. Claim iat is set to current time, exp is set by WithTTL
+	// and iss by WithIssuer unless claims have them
+	//
+	// Ex.: token, err := j.Issue(jvm.M{"sub": userID, "role": "admin"})
+	Issue(claims jvm.M) (string, error)
+	// Verifies signature, exp, nbf and configured iss and aud and returns claims.
+	// Numbers of claims are json.Number
+	Parse(token string) (jvm.M, error)
+	// Parses token from header "Authorization: Bearer <token>" or cookie set by WithCookie
+	//
+	// Ex.: claims, err := j.ParseFromRequest(r); if err != nil { httpx.Error(w, err); return }
+	ParseFromRequest(r *http.Request) (jvm.M, error)
+}
+
+// Constructor of issuer and verifier
+//
+// Ex.: jwt.New(jwt.StaticKeys(jwt.HS256("v1", secret)), jwt.WithTTL(time.Hour), jwt.WithSkew(30*time.Second))
+func New(keys KeyProvider, opts ...Option) JWT {
+	j := &jwt{
+		config: config{
+			now: time.Now,
+		},
+		keys: keys,
+	}
+	for _, opt := range opts {
+		opt(&j.config)
+	}
+	return j
+}
+
+// Lifetime of issued tokens. Zero means tokens without exp
+func WithTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+// Allowed difference of clocks for exp and nbf checks
+func WithSkew(skew time.Duration) Option {
+	return func(c *config) {
+		c.skew = skew
+	}
+}
+
+// Sets iss of issued tokens and requires it in parsed ones
+func WithIssuer(issuer string) Option {
+	return func(c *config) {
+		c.issuer = issuer
+	}
+}
+
+// Requires aud of parsed tokens to contain audience
+func WithAudience(audience string) Option {
+	return func(c *config) {
+		c.audience = audience
+	}
+}
+
+// Reads token from cookie if request has no Authorization header
+func WithCookie(name string) Option {
+	return func(c *config) {
+		c.cookie = name
+	}
+}
+
+// Source of current time
+func WithClock(now func() time.Time) Option {
+	return func(c *config) {
+		c.now = now
+	}
+}
+
+// Returns context carrying claims
+func NewContext(ctx context.Context, claims jvm.M) context.Context {
+	return context.WithValue(ctx, contextKey{}, claims)
+}
+
+// Returns claims of context
+//
+// Ex.: claims, ok := jwt.FromContext(r.Context())
+func FromContext(ctx context.Context) (jvm.M, bool) {
+	claims, ok := ctx.Value(contextKey{}).(jvm.M)
+	return claims, ok
+}
+
+func (j *jwt) Issue(claims jvm.M) (string, error) {
+	key, err := j.keys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+	claims = claims.Copy()
+	now := j.now()
+	setDefault(claims, "iat", now.Unix())
+	if j.ttl > 0 {
+		setDefault(claims, "exp", now.Add(j.ttl).Unix())
+	}
+	if j.issuer != "" {
+		setDefault(claims, "iss", j.issuer)
+	}
+
+	h, err := json.Marshal(header{Alg: key.Algorithm, Typ: "JWT", Kid: key.ID})
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	input := encode(h) + "." + encode(c)
+	sig, err := sign(key, input)
+	if err != nil {
+		return "", err
+	}
+	return input + "." + encode(sig), nil
+}
+
+func (j *jwt) Parse(token string) (jvm.M, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalid)
+	}
+	var h header
+	if err := decodePart(parts[0], &h); err != nil {
+		return nil, err
+	}
+	key, err := j.keys.VerificationKey(h.Kid)
+	if err != nil {
+		return nil, err
+	}
+	// algorithm of token must match the key to prevent algorithm substitution
+	if h.Alg != key.Algorithm {
+		return nil, fmt.Errorf("%w: unexpected algorithm %q", ErrInvalid, h.Alg)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalid)
+	}
+	if err := verify(key, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, err
+	}
+	var claims jvm.M
+	if err := decodePart(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if err := j.validate(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (j *jwt) ParseFromRequest(r *http.Request) (jvm.M, error) {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		scheme, token, ok := strings.Cut(auth, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return nil, fmt.Errorf("%w: authorization scheme must be Bearer", ErrInvalid)
+		}
+		return j.Parse(strings.TrimSpace(token))
+	}
+	if j.cookie != "" {
+		if c, err := r.Cookie(j.cookie); err == nil && c.Value != "" {
+			return j.Parse(c.Value)
+		}
+	}
+	return nil, ErrNoToken
+}
+
+// Checks registered claims
+func (j *jwt) validate(claims jvm.M) error {
+	now := j.now()
+	if exp, ok, err := numericDate(claims, "exp"); err != nil {
+		return err
+	} else if ok && !now.Before(exp.Add(j.skew)) {
+		return ErrExpired
+	}
+	if nbf, ok, err := numericDate(claims, "nbf"); err != nil {
+		return err
+	} else if ok && now.Add(j.skew).Before(nbf) {
+		return ErrNotYetValid
+	}
+	if j.issuer != "" && claims["iss"] != j.issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalid)
+	}
+	if j.audience != "" && !hasAudience(claims["aud"], j.audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalid)
+	}
+	return nil
+}
+
+func sign(key Key, input string) ([]byte, error) {
+	switch key.Algorithm {
+	case AlgHS256:
+		if err := checkSecret(key); err != nil {
+			return nil, err
+		}
+		mac := hmac.New(sha256.New, key.Secret)
+		mac.Write([]byte(input))
+		return mac.Sum(nil), nil
+	case AlgRS256:
+		if key.PrivateKey == nil {
+			return nil, fmt.Errorf("%w: key %q has no private key", jve.ErrInvalid, key.ID)
+		}
+		digest := sha256.Sum256([]byte(input))
+		return rsa.SignPKCS1v15(rand.Reader, key.PrivateKey, crypto.SHA256, digest[:])
+	}
+	return nil, fmt.Errorf("%w: unsupported algorithm %q", jve.ErrInvalid, key.Algorithm)
+}
+
+func verify(key Key, input string, sig []byte) error {
+	switch key.Algorithm {
+	case AlgHS256:
+		if err := checkSecret(key); err != nil {
+			return err
+		}
+		mac := hmac.New(sha256.New, key.Secret)
+		mac.Write([]byte(input))
+		if hmac.Equal(mac.Sum(nil), sig) {
+			return nil
+		}
+	case AlgRS256:
+		digest := sha256.Sum256([]byte(input))
+		if key.PublicKey != nil && rsa.VerifyPKCS1v15(key.PublicKey, crypto.SHA256, digest[:], sig) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: bad signature", ErrInvalid)
+}
+
+// Rejects HS256 secrets which are empty or short enough to be guessed.
+// Short secret is misconfiguration of server, so error is internal
+func checkSecret(key Key) error {
+	if len(key.Secret) < MinSecretSize {
+		return fmt.Errorf("%w: secret of key %q is shorter than %d bytes", jve.ErrInternal, key.ID, MinSecretSize)
+	}
+	return nil
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodePart(part string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return fmt.Errorf("%w: malformed", ErrInvalid)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%w: malformed", ErrInvalid)
+	}
+	return nil
+}
+
+func setDefault(m jvm.M, key string, value any) {
+	if _, ok := m[key]; !ok {
+		m[key] = value
+	}
+}
+
+// Returns time of numeric date claim
+func numericDate(claims jvm.M, name string) (time.Time, bool, error) {
+	v, ok := claims[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	n, isNumber := v.(json.Number)
+	if !isNumber {
+		return time.Time{}, false, fmt.Errorf("%w: claim %s must be number", ErrInvalid, name)
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%w: claim %s must be number", ErrInvalid, name)
+	}
+	return time.UnixMilli(int64(f * 1000)), true, nil
+}
+
+// Audience claim is string or array of strings
+func hasAudience(aud any, audience string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == audience
+	case []any:
+		return slices.Contains(v, any(audience))
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package jwt
+
+import (
+	"crypto/rsa"
+	"fmt"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+// Minimal length of HS256 secret in bytes. Tokens are neither signed nor verified by shorter secrets
+const MinSecretSize = 32
+
+// Key of token signature. Secret is used by HS256, private and public keys by RS256
+type Key struct {
+	ID         string
+	Algorithm  string
+	Secret     []byte
+	PrivateKey *rsa.PrivateKey
+	PublicKey  *rsa.PublicKey
+}
+
+// Source of keys. Implementations may rotate keys: new tokens are signed by the current key
+// while tokens of previous keys are still verified
+type KeyProvider interface {
+	// Key for signing new tokens
+	SigningKey() (Key, error)
+	// Key for verification by ID from token header. ID is empty if token has no kid
+	VerificationKey(id string) (Key, error)
+}
+
+type staticKeys struct {
+	signing Key
+	keys    map[string]Key
+}
+
+// HS256 key constructor. Secret must be at least MinSecretSize random bytes
+//
+// Ex.: jwt.HS256("2024-05", secret) // secret, _ := hex.DecodeString(os.Getenv("JWT_SECRET"))
+func HS256(id string, secret []byte) Key {
+	return Key{
+		ID:        id,
+		Algorithm: AlgHS256,
+		Secret:    secret,
+	}
+}
+
+// RS256 key constructor for signing and verification
+func RS256(id string, private *rsa.PrivateKey) Key {
+	return Key{
+		ID:         id,
+		Algorithm:  AlgRS256,
+		PrivateKey: private,
+		PublicKey:  &private.PublicKey,
+	}
+}
+
+// RS256 key constructor for verification only
+func RS256Public(id string, public *rsa.PublicKey) Key {
+	return Key{
+		ID:        id,
+		Algorithm: AlgRS256,
+		PublicKey: public,
+	}
+}
+
+// Fixed set of keys. Tokens are signed by signing key and verified by any key.
+// Previous keys are kept for verification of tokens issued before rotation
+//
+// Ex.: jwt.StaticKeys(jwt.HS256("v2", secret2), jwt.HS256("v1", secret1))
+func StaticKeys(signing Key, previous ...Key) KeyProvider {
+	s := &staticKeys{
+		signing: signing,
+		keys:    make(map[string]Key, len(previous)+1),
+	}
+	for _, k := range append([]Key{signing}, previous...) {
+		if _, ok := s.keys[k.ID]; !ok {
+			s.keys[k.ID] = k
+		}
+	}
+	return s
+}
+
+func (s *staticKeys) SigningKey() (Key, error) {
+	return s.signing, nil
+}
+
+// Token without kid is verified by signing key
+func (s *staticKeys) VerificationKey(id string) (Key, error) {
+	if id == "" {
+		return s.signing, nil
+	}
+	k, ok := s.keys[id]
+	if !ok {
+		return Key{}, fmt.Errorf("%w: unknown key %q", ErrInvalid, id)
+	}
+	return k, nil
+}
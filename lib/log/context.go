@@ -0,0 +1,22 @@
+package log
+
+import "context"
+
+type contextKey struct{}
+
+// Returns context carrying logger
+//
+// Ex.: ctx = log.NewContext(ctx, log.With(jvm.M{"request_id": id}))
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// Returns logger of context or default logger if there is none
+//
+// Ex.: log.FromContext(ctx).Info("order created", jvm.M{"order_id": id})
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(contextKey{}).(Logger); ok {
+		return l
+	}
+	return Default()
+}
@@ -0,0 +1,109 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+)
+
+// Log entry
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  jvm.M
+}
+
+// Converts entry into bytes written to output
+type Encoder interface {
+	// Returns encoded entry including trailing new line
+	Encode(e Entry) []byte
+}
+
+type jsonEncoder struct{}
+
+type consoleEncoder struct{}
+
+// Encodes entry as JSON object with keys time, level, msg followed by fields in sorted order
+//
+// Ex.: {"time":"2024-05-01T10:00:00Z","level":"info","msg":"started","port":8080}
+func JSONEncoder() Encoder {
+	return jsonEncoder{}
+}
+
+// Encodes entry as human readable line with fields in sorted order
+//
+// Ex.: 2024-05-01T10:00:00Z INFO started port=8080
+func ConsoleEncoder() Encoder {
+	return consoleEncoder{}
+}
+
+func (jsonEncoder) Encode(e Entry) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`{"time":`)
+	writeJSON(&buf, e.Time.Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJSON(&buf, e.Level.String())
+	buf.WriteString(`,"msg":`)
+	writeJSON(&buf, e.Message)
+	for _, key := range sortedKeys(e.Fields) {
+		buf.WriteByte(',')
+		writeJSON(&buf, key)
+		buf.WriteByte(':')
+		writeJSON(&buf, fieldValue(e.Fields[key]))
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+func (consoleEncoder) Encode(e Entry) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(e.Time.Format(time.RFC3339))
+	buf.WriteByte(' ')
+	buf.WriteString(strings.ToUpper(e.Level.String()))
+	buf.WriteByte(' ')
+	buf.WriteString(e.Message)
+	for _, key := range sortedKeys(e.Fields) {
+		buf.WriteByte(' ')
+		buf.WriteString(key)
+		buf.WriteByte('=')
+		s := fmt.Sprint(fieldValue(e.Fields[key]))
+		if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+			s = strconv.Quote(s)
+		}
+		buf.WriteString(s)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+// Errors are encoded by message, other values as is
+func fieldValue(v any) any {
+	if err, ok := v.(error); ok {
+		return err.Error()
+	}
+	return v
+}
+
+func writeJSON(buf *bytes.Buffer, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprint(v))
+	}
+	buf.Write(b)
+}
+
+func sortedKeys(m jvm.M) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,226 @@
+package log
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+)
+
+// Severity of entry
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+type output struct {
+	mu      sync.Mutex
+	w       io.Writer
+	encoder Encoder
+	level   Level
+}
+
+type logger struct {
+	out    *output
+	fields jvm.M
+}
+
+// Option of logger
+type Option func(*output)
+
+// Leveled logger with structured fields. Safe for concurrent use
+type Logger interface {
+	// Logs entry with debug level. Fields of several maps are merged
+	//
+	// Ex.: l.Debug("cache miss", jvm.M{"key": key})
+	Debug(msg string, fields ...jvm.M)
+	// Logs entry with info level
+	Info(msg string, fields ...jvm.M)
+	// Logs entry with warn level
+	Warn(msg string, fields ...jvm.M)
+	// Logs entry with error level
+	//
+	// Ex.: l.Error("load user", log.Err(err))
+	Error(msg string, fields ...jvm.M)
+	// Returns logger adding fields to every entry
+	//
+	// Ex.: l := logger.With(jvm.M{"request_id": id})
+	With(fields jvm.M) Logger
+	// Returns true if entries of level are written
+	Enabled(level Level) bool
+}
+
+var defaultLogger atomic.Pointer[Logger]
+
+func init() {
+	SetDefault(New(os.Stderr))
+}
+
+// Logger constructor. By default entries of info level and above are written in JSON
+//
+// Ex.: log.New(os.Stdout, log.WithLevel(log.LevelDebug), log.WithEncoder(log.ConsoleEncoder()))
+func New(w io.Writer, opts ...Option) Logger {
+	out := &output{
+		w:       w,
+		encoder: JSONEncoder(),
+		level:   LevelInfo,
+	}
+	for _, opt := range opts {
+		opt(out)
+	}
+	return &logger{out: out}
+}
+
+// Minimum level of written entries
+func WithLevel(level Level) Option {
+	return func(o *output) {
+		o.level = level
+	}
+}
+
+// Encoder of entries
+func WithEncoder(e Encoder) Option {
+	return func(o *output) {
+		o.encoder = e
+	}
+}
+
+// Returns default logger
+func Default() Logger {
+	return *defaultLogger.Load()
+}
+
+// Replaces default logger used by package functions
+func SetDefault(l Logger) {
+	defaultLogger.Store(&l)
+}
+
+// Logs entry with debug level to default logger
+func Debug(msg string, fields ...jvm.M) {
+	Default().Debug(msg, fields...)
+}
+
+// Logs entry with info level to default logger
+func Info(msg string, fields ...jvm.M) {
+	Default().Info(msg, fields...)
+}
+
+// Logs entry with warn level to default logger
+func Warn(msg string, fields ...jvm.M) {
+	Default().Warn(msg, fields...)
+}
+
+// Logs entry with error level to default logger
+func Error(msg string, fields ...jvm.M) {
+	Default().Error(msg, fields...)
+}
+
+// Returns default logger adding fields to every entry
+//
+// Ex.: l := log.With(jvm.M{"request_id": id})
+func With(fields jvm.M) Logger {
+	return Default().With(fields)
+}
+
+// Fields of error: message in "error" and fields attached by errors.WithFields
+//
+// Ex.: log.Error("save order", log.Err(err))
+func Err(err error) jvm.M {
+	if err == nil {
+		return nil
+	}
+	fields := jve.Fields(err)
+	fields["error"] = err.Error()
+	return fields
+}
+
+// Parses level name case-insensitively. Unknown names are parsed as info
+//
+// Ex.: log.WithLevel(log.ParseLevel(env.String("LOG_LEVEL", "info")))
+func ParseLevel(s string) Level {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug
+	case "warn", "warning":
+		return LevelWarn
+	case "error":
+		return LevelError
+	}
+	return LevelInfo
+}
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return "unknown"
+}
+
+func (l *logger) Debug(msg string, fields ...jvm.M) {
+	l.log(LevelDebug, msg, fields)
+}
+
+func (l *logger) Info(msg string, fields ...jvm.M) {
+	l.log(LevelInfo, msg, fields)
+}
+
+func (l *logger) Warn(msg string, fields ...jvm.M) {
+	l.log(LevelWarn, msg, fields)
+}
+
+func (l *logger) Error(msg string, fields ...jvm.M) {
+	l.log(LevelError, msg, fields)
+}
+
+func (l *logger) With(fields jvm.M) Logger {
+	return &logger{
+		out:    l.out,
+		fields: merge(l.fields, fields),
+	}
+}
+
+func (l *logger) Enabled(level Level) bool {
+	return level >= l.out.level
+}
+
+func (l *logger) log(level Level, msg string, fields []jvm.M) {
+	if !l.Enabled(level) {
+		return
+	}
+	b := l.out.encoder.Encode(Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: msg,
+		Fields:  merge(l.fields, fields...),
+	})
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.w.Write(b)
+}
+
+// Merges maps into new one. Later fields take precedence
+func merge(base jvm.M, maps ...jvm.M) jvm.M {
+	result := base.Copy()
+	for _, m := range maps {
+		for k, v := range m {
+			result[k] = v
+		}
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"time"
+
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+	"github.com/jvnonce/jv-go-utils/lib/qb"
+)
+
+type queryLogger struct {
+	l     Logger
+	level Level
+}
+
+// Adapter of logger for qb.SetQueryLogger. Queries are logged with level,
+// failed queries with error level
+//
+// Ex.: qb.SetQueryLogger(log.QueryLogger(log.Default(), log.LevelDebug))
+func QueryLogger(l Logger, level Level) qb.QueryLogger {
+	return &queryLogger{
+		l:     l,
+		level: level,
+	}
+}
+
+func (q *queryLogger) LogQuery(sql string, args []any, duration time.Duration, err error) {
+	level := q.level
+	if err != nil {
+		level = LevelError
+	}
+	if !q.l.Enabled(level) {
+		return
+	}
+	fields := jvm.M{
+		"sql":         sql,
+		"args":        args,
+		"duration_ms": float64(duration.Microseconds()) / 1000,
+	}
+	switch level {
+	case LevelDebug:
+		q.l.Debug("query", fields)
+	case LevelInfo:
+		q.l.Info("query", fields)
+	case LevelWarn:
+		q.l.Warn("query", fields)
+	default:
+		q.l.Error("query", fields, Err(err))
+	}
+}
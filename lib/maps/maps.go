@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"database/sql/driver"
 	"encoding/json"
+	"errors"
 	"strings"
 
 	"github.com/iancoleman/strcase"
-	jve "github.com/jvnonce/jv-go-utils/lib/errors"
 )
 
+// Scanned value is not JSON bytes. Also exported as errors.ErrBadType
+var ErrBadType = errors.New("bad type error")
+
 type Mapping interface {
 	ToMap(fields ...string) M
 }
@@ -56,7 +59,7 @@ func (m *M) Scan(value interface{}) error {
 	}
 	bytes, ok := value.([]byte)
 	if !ok {
-		return jve.ErrBadType
+		return ErrBadType
 	}
 
 	var result map[string]interface{}
@@ -212,7 +215,7 @@ func (a *A) Scan(value interface{}) error {
 	}
 	bytes, ok := value.([]byte)
 	if !ok {
-		return jve.ErrBadType
+		return ErrBadType
 	}
 
 	var result []interface{}
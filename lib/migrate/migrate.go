@@ -0,0 +1,346 @@
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+)
+
+const (
+	defaultTable   = "schema_migrations"
+	defaultLockKey = 7245190340164118
+)
+
+// Migration parsed from files
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status of migration
+type Status struct {
+	Version   int64     `json:"version"`
+	Name      string    `json:"name"`
+	Applied   bool      `json:"applied"`
+	AppliedAt time.Time `json:"applied_at,omitempty"`
+	// Migration is applied, but its files are missing
+	Missing bool `json:"missing,omitempty"`
+}
+
+// Runs queries on the locked connection or on the pool
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+type migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	table      string
+	lockKey    int64
+}
+
+// Option of migrator
+type Option func(*migrator)
+
+// Applies and reverts migrations. Every migration runs in a transaction together with
+// update of migrations table, so scripts must not contain their own BEGIN or COMMIT and
+// statements which can't run in transaction are not supported.
+// Concurrent migrators are serialized by PostgreSQL advisory lock
+type Migrator interface {
+	// Applies all pending migrations in order of versions. Returns versions of applied migrations
+	Up(ctx context.Context) ([]int64, error)
+	// Reverts the given count of last applied migrations. Returns versions of reverted migrations.
+	// Steps must be positive
+	Down(ctx context.Context, steps int) ([]int64, error)
+	// Returns status of known and applied migrations ordered by version
+	Status(ctx context.Context) ([]Status, error)
+}
+
+// Migrator constructor. Migrations are .sql files in the root of file system named
+// <version>_<name>.up.sql and <version>_<name>.down.sql. File <version>_<name>.sql is up migration
+//
+// Ex.: //go:embed migrations/*.sql
+// var files embed.FS
+// m, err := migrate.New(db, errors.Must(fs.Sub(files, "migrations")))
+func New(db *sql.DB, fsys fs.FS, opts ...Option) (Migrator, error) {
+	migrations, err := parse(fsys)
+	if err != nil {
+		return nil, err
+	}
+	m := &migrator{
+		db:         db,
+		migrations: migrations,
+		table:      defaultTable,
+		lockKey:    defaultLockKey,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
+}
+
+// Migrator constructor for directory
+//
+// Ex.: migrate.NewFromDir(db, "./migrations")
+func NewFromDir(db *sql.DB, dir string, opts ...Option) (Migrator, error) {
+	return New(db, os.DirFS(dir), opts...)
+}
+
+// Name of table with applied migrations. Default is schema_migrations
+func WithTable(table string) Option {
+	return func(m *migrator) {
+		m.table = table
+	}
+}
+
+// Key of advisory lock. Migrators of different schemas in one database may use different keys
+func WithLockKey(key int64) Option {
+	return func(m *migrator) {
+		m.lockKey = key
+	}
+}
+
+func (m *migrator) Up(ctx context.Context) ([]int64, error) {
+	var done []int64
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		record := "INSERT INTO " + m.table + " (version, name) VALUES ($1, $2)"
+		for _, mg := range m.migrations {
+			if _, ok := applied[mg.Version]; ok {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := inTx(ctx, conn, mg.Up, record, mg.Version, mg.Name); err != nil {
+				return jve.Wrapf(err, "migration %d %s", mg.Version, mg.Name)
+			}
+			done = append(done, mg.Version)
+		}
+		return nil
+	})
+	return done, err
+}
+
+func (m *migrator) Down(ctx context.Context, steps int) ([]int64, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("%w: steps must be positive, got %d", jve.ErrInvalid, steps)
+	}
+	var done []int64
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]int64, 0, len(applied))
+		for v := range applied {
+			versions = append(versions, v)
+		}
+		slices.Sort(versions)
+		slices.Reverse(versions)
+
+		for _, v := range versions[:min(steps, len(versions))] {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			mg, ok := m.find(v)
+			if !ok {
+				return fmt.Errorf("%w: files of migration %d", jve.ErrNotFound, v)
+			}
+			if mg.Down == "" {
+				return fmt.Errorf("%w: migration %d %s has no down script", jve.ErrInvalid, mg.Version, mg.Name)
+			}
+			record := "DELETE FROM " + m.table + " WHERE version = $1"
+			if err := inTx(ctx, conn, mg.Down, record, v); err != nil {
+				return jve.Wrapf(err, "revert migration %d %s", mg.Version, mg.Name)
+			}
+			done = append(done, v)
+		}
+		return nil
+	})
+	return done, err
+}
+
+func (m *migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.createTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Status, 0, len(m.migrations))
+	for _, mg := range m.migrations {
+		s := Status{
+			Version: mg.Version,
+			Name:    mg.Name,
+		}
+		if a, ok := applied[mg.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = a.AppliedAt
+			delete(applied, mg.Version)
+		}
+		result = append(result, s)
+	}
+	for _, a := range applied {
+		result = append(result, a)
+	}
+	slices.SortFunc(result, func(a, b Status) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return result, nil
+}
+
+// Runs fn holding advisory lock. Lock is bound to session,
+// so it is taken on a dedicated connection of the pool which is passed to fn
+func (m *migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", m.lockKey); err != nil {
+		return jve.FromPG(err)
+	}
+	defer func() {
+		var unlocked bool
+		unlockErr := conn.QueryRowContext(context.Background(), "SELECT pg_advisory_unlock($1)", m.lockKey).Scan(&unlocked)
+		if unlockErr == nil && !unlocked {
+			unlockErr = fmt.Errorf("%w: advisory lock %d is not held", jve.ErrInternal, m.lockKey)
+		}
+		if unlockErr != nil {
+			err = jve.Join(err, jve.Wrap(jve.FromPG(unlockErr), "release advisory lock"))
+		}
+	}()
+
+	if err := m.createTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// Runs script and update of migrations table in one transaction
+func inTx(ctx context.Context, conn *sql.Conn, script string, record string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return jve.FromPG(err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return jve.FromPG(err)
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return jve.FromPG(err)
+	}
+	return jve.FromPG(tx.Commit())
+}
+
+func (m *migrator) createTable(ctx context.Context, q querier) error {
+	_, err := q.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+m.table+" (\n"+
+		"version bigint PRIMARY KEY,\n"+
+		"name text NOT NULL,\n"+
+		"applied_at timestamptz NOT NULL DEFAULT now()\n"+
+		")")
+	return jve.FromPG(err)
+}
+
+// Returns applied migrations by version
+func (m *migrator) applied(ctx context.Context, q querier) (map[int64]Status, error) {
+	rows, err := q.QueryContext(ctx, "SELECT version, name, applied_at FROM "+m.table)
+	if err != nil {
+		return nil, jve.FromPG(err)
+	}
+	defer rows.Close()
+	result := make(map[int64]Status)
+	for rows.Next() {
+		s := Status{Applied: true}
+		if err := rows.Scan(&s.Version, &s.Name, &s.AppliedAt); err != nil {
+			return nil, jve.FromPG(err)
+		}
+		_, known := m.find(s.Version)
+		s.Missing = !known
+		result[s.Version] = s
+	}
+	return result, jve.FromPG(rows.Err())
+}
+
+func (m *migrator) find(version int64) (Migration, bool) {
+	i, ok := slices.BinarySearchFunc(m.migrations, version, func(mg Migration, v int64) int {
+		return cmp.Compare(mg.Version, v)
+	})
+	if !ok {
+		return Migration{}, false
+	}
+	return m.migrations[i], true
+}
+
+// Reads migrations from .sql files ordered by version
+func parse(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	type script struct {
+		version int64
+		down    bool
+	}
+	byVersion := make(map[int64]*Migration)
+	seen := make(map[script]bool)
+	for _, file := range files {
+		base := strings.TrimSuffix(path.Base(file), ".sql")
+		down := strings.HasSuffix(base, ".down")
+		base = strings.TrimSuffix(strings.TrimSuffix(base, ".down"), ".up")
+		num, name, _ := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(num, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: migration file %q must start with version", jve.ErrBadFormat, file)
+		}
+		b, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &Migration{Version: version, Name: name}
+			byVersion[version] = mg
+		} else if mg.Name != name {
+			return nil, fmt.Errorf("%w: migrations %q and %q have the same version", jve.ErrConflict, mg.Name, name)
+		}
+		key := script{version, down}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: migration %d has several files", jve.ErrConflict, version)
+		}
+		seen[key] = true
+		if down {
+			mg.Down = string(b)
+		} else {
+			mg.Up = string(b)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, mg := range byVersion {
+		result = append(result, *mg)
+	}
+	slices.SortFunc(result, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return result, nil
+}
@@ -0,0 +1,130 @@
+package null
+
+import (
+	"database/sql/driver"
+
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+	"github.com/jvnonce/jv-go-utils/lib/ptr"
+)
+
+// Nullable value with exported fields in the shape of sql.Null. Invalid value is encoded as
+// JSON null and SQL NULL, so it can be scanned from nullable column, passed as qb parameter
+// and returned in JSON payload. Encoding is done by ptr.Optional, use it when absent field
+// of JSON object must be told from null. Zero Null is invalid
+//
+// Ex.: type User struct { Phone null.Null[string] `json:"phone"` }
+type Null[T any] struct {
+	V     T
+	Valid bool
+}
+
+// Valid value
+//
+// Ex.: qb.New(db).Select("users").Where("phone = ?", null.From(phone))
+func From[T any](v T) Null[T] {
+	return Null[T]{
+		V:     v,
+		Valid: true,
+	}
+}
+
+// Invalid value
+func Empty[T any]() Null[T] {
+	return Null[T]{}
+}
+
+// Value from pointer. Nil pointer is invalid value
+func FromPtr[T any](p *T) Null[T] {
+	return FromOptional(ptr.FromPtr(p))
+}
+
+// Value from optional. Absent and null optionals are invalid value
+func FromOptional[T any](o ptr.Optional[T]) Null[T] {
+	v, ok := o.Get()
+	return Null[T]{
+		V:     v,
+		Valid: ok,
+	}
+}
+
+// Value from any source supported by sql.Scanner, e.g. value of map or row of qb.
+// Value of type T is used as is. Nil is invalid value
+//
+// Ex.: id, err := null.FromAny[int64](row["parent_id"])
+func FromAny[T any](value any) (Null[T], error) {
+	if v, ok := value.(T); ok {
+		return From(v), nil
+	}
+	var n Null[T]
+	err := n.Scan(value)
+	return n, err
+}
+
+// Value of map key. Absent key and nil are invalid value
+//
+// Ex.: parent, err := null.Get[int64](row, "parent_id")
+func Get[T any](m jvm.M, key string) (Null[T], error) {
+	return FromAny[T](m[key])
+}
+
+// Puts value into map, invalid value is put as nil
+//
+// Ex.: null.Put(params, "deleted_at", user.DeletedAt)
+func Put[T any](m jvm.M, key string, n Null[T]) {
+	ptr.Put(m, key, n.Optional())
+}
+
+// Returns optional with value or explicitly null optional if value is invalid
+func (n Null[T]) Optional() ptr.Optional[T] {
+	if !n.Valid {
+		return ptr.Null[T]()
+	}
+	return ptr.Some(n.V)
+}
+
+// Returns pointer to copy of value or nil if it is invalid
+func (n Null[T]) Ptr() *T {
+	return n.Optional().Ptr()
+}
+
+// Returns value or default value if it is invalid
+func (n Null[T]) OrElse(defaultValue T) T {
+	return n.Optional().OrElse(defaultValue)
+}
+
+// Returns value or nil if it is invalid
+func (n Null[T]) Any() any {
+	if !n.Valid {
+		return nil
+	}
+	return n.V
+}
+
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	return n.Optional().MarshalJSON()
+}
+
+// Unmarshaller for nullable value. JSON null is invalid value
+func (n *Null[T]) UnmarshalJSON(b []byte) error {
+	var o ptr.Optional[T]
+	if err := o.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	*n = FromOptional(o)
+	return nil
+}
+
+// Scanner for nullable value. NULL is invalid value
+func (n *Null[T]) Scan(value any) error {
+	var o ptr.Optional[T]
+	if err := o.Scan(value); err != nil {
+		return err
+	}
+	*n = FromOptional(o)
+	return nil
+}
+
+// Value for database. Invalid value is NULL
+func (n Null[T]) Value() (driver.Value, error) {
+	return n.Optional().Value()
+}
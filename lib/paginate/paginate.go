@@ -0,0 +1,153 @@
+package paginate
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+	"github.com/jvnonce/jv-go-utils/lib/httpx"
+	"github.com/jvnonce/jv-go-utils/lib/qb"
+)
+
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+// Requested page. Cursor is used instead of page number for keyset pagination
+type PageRequest struct {
+	Page    int    `json:"page"`
+	PerPage int    `json:"per_page"`
+	Cursor  string `json:"cursor,omitempty"`
+}
+
+// Page of results
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Parses page request from query parameters page, per_page and cursor.
+// Page defaults to 1, per_page defaults to DefaultPerPage and is limited by MaxPerPage
+//
+// Ex.: req, err := paginate.FromRequest(r) // ?page=2&per_page=50
+func FromRequest(r *http.Request) (PageRequest, error) {
+	q := httpx.Query(r, "page", "per_page", "cursor")
+	p := PageRequest{
+		Page:    1,
+		PerPage: DefaultPerPage,
+	}
+	var err error
+	if s, ok := q["page"].(string); ok {
+		if p.Page, err = strconv.Atoi(s); err != nil || p.Page < 1 {
+			return p, fmt.Errorf("%w: page must be positive integer", jve.ErrInvalid)
+		}
+	}
+	if s, ok := q["per_page"].(string); ok {
+		if p.PerPage, err = strconv.Atoi(s); err != nil || p.PerPage < 1 {
+			return p, fmt.Errorf("%w: per_page must be positive integer", jve.ErrInvalid)
+		}
+		p.PerPage = min(p.PerPage, MaxPerPage)
+	}
+	if s, ok := q["cursor"].(string); ok {
+		p.Cursor = s
+	}
+	return p, nil
+}
+
+// Returns true if keyset pagination is requested
+func (p PageRequest) IsCursor() bool {
+	return p.Cursor != ""
+}
+
+// Count of rows to skip for page number
+func (p PageRequest) Offset() int {
+	return max(p.Page-1, 0) * p.PerPage
+}
+
+// Adds limit and offset of page to select query.
+// One extra row is selected to detect the next page, NewPage removes it
+//
+// Ex.: rows, err := req.Apply(qb.New(db).Select("users").OrderBy("id", "ASC")).Rows()
+func (p PageRequest) Apply(q qb.QueryBuilder) qb.QueryBuilder {
+	return q.Limit(p.PerPage + 1).Offset(p.Offset())
+}
+
+// Adds keyset condition after the cursor and limit of page to select query.
+// Columns must be unique together, see qb Keyset.
+// One extra row is selected to detect the next page, NewKeysetPage removes it
+//
+// Ex.: q, err := req.ApplyKeyset(qb.New(db).Select("posts"), []string{"created_at", "id"}, "DESC")
+func (p PageRequest) ApplyKeyset(q qb.QueryBuilder, columns []string, direction string) (qb.QueryBuilder, error) {
+	var after []any
+	if p.IsCursor() {
+		var err error
+		if after, err = DecodeCursor(p.Cursor); err != nil {
+			return q, err
+		}
+		if len(after) != len(columns) {
+			return q, fmt.Errorf("%w: bad cursor", jve.ErrBadFormat)
+		}
+	}
+	return q.Keyset(columns, direction, after).Limit(p.PerPage + 1), nil
+}
+
+// Page constructor for rows selected by Apply
+//
+// Ex.: httpx.WriteJSON(w, http.StatusOK, paginate.NewPage(req, rows))
+func NewPage[T any](p PageRequest, items []T) Page[T] {
+	page := Page[T]{
+		Items:   items,
+		Page:    p.Page,
+		PerPage: p.PerPage,
+	}
+	if len(items) > p.PerPage {
+		page.Items = items[:p.PerPage]
+		page.HasMore = true
+	}
+	return page
+}
+
+// Page constructor for rows selected by ApplyKeyset. Key returns values of keyset columns of item
+//
+// Ex.: paginate.NewKeysetPage(req, rows, func(row jvm.M) []any { return []any{row["created_at"], row["id"]} })
+func NewKeysetPage[T any](p PageRequest, items []T, key func(item T) []any) Page[T] {
+	page := NewPage(p, items)
+	page.Page = 0
+	if page.HasMore {
+		page.NextCursor = EncodeCursor(key(page.Items[len(page.Items)-1])...)
+	}
+	return page
+}
+
+// Encodes values of keyset columns as opaque cursor
+func EncodeCursor(values ...any) string {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Decodes values of keyset columns from cursor. Numbers are decoded as json.Number
+// to keep precision of big identifiers
+func DecodeCursor(cursor string) ([]any, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad cursor", jve.ErrBadFormat)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v []any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("%w: bad cursor", jve.ErrBadFormat)
+	}
+	return v, nil
+}
@@ -0,0 +1,142 @@
+package ptr
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+)
+
+// Value which may be absent, explicitly null or set. Absent field of JSON object is absent value,
+// JSON null is null value, so partial updates can tell "don't change" from "set to NULL".
+// Absent and null values are encoded as JSON null and SQL NULL, SQL NULL is scanned as null value.
+// Zero Optional is absent
+//
+// Ex.: type UserPatch struct { Name ptr.Optional[string] `json:"name"` }
+type Optional[T any] struct {
+	value T
+	set   bool
+	null  bool
+}
+
+// Optional with value
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{
+		value: v,
+		set:   true,
+	}
+}
+
+// Absent optional
+func None[T any]() Optional[T] {
+	return Optional[T]{}
+}
+
+// Explicitly null optional
+func Null[T any]() Optional[T] {
+	return Optional[T]{
+		null: true,
+	}
+}
+
+// Optional from pointer. Nil pointer is absent value
+func FromPtr[T any](p *T) Optional[T] {
+	if p == nil {
+		return None[T]()
+	}
+	return Some(*p)
+}
+
+// Puts value of optional into map unless it is absent, null value is put as nil.
+// Intended for partial updates
+//
+// Ex.: patch := jvm.New(); ptr.Put(patch, "name", req.Name); qb.Update("users").ColsWithParams(patch)
+func Put[T any](m jvm.M, key string, o Optional[T]) {
+	switch {
+	case o.set:
+		m[key] = o.value
+	case o.null:
+		m[key] = nil
+	}
+}
+
+// Returns value and true if it is set
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.set
+}
+
+// Returns true if value is set
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// Returns true if value is explicitly null
+func (o Optional[T]) IsNull() bool {
+	return o.null
+}
+
+// Returns true if value is neither set nor null
+func (o Optional[T]) IsAbsent() bool {
+	return !o.set && !o.null
+}
+
+// Returns value or default value if it is not set
+func (o Optional[T]) OrElse(defaultValue T) T {
+	if !o.set {
+		return defaultValue
+	}
+	return o.value
+}
+
+// Returns pointer to copy of value or nil if it is not set
+func (o Optional[T]) Ptr() *T {
+	if !o.set {
+		return nil
+	}
+	return Ptr(o.value)
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.set {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+// Unmarshaller for optional. JSON null is null value
+func (o *Optional[T]) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(bytes.TrimSpace(b), []byte("null")) {
+		*o = Null[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*o = Some(v)
+	return nil
+}
+
+// Scanner for optional. NULL is null value
+func (o *Optional[T]) Scan(value any) error {
+	var n sql.Null[T]
+	if err := n.Scan(value); err != nil {
+		return err
+	}
+	*o = Optional[T]{
+		value: n.V,
+		set:   n.Valid,
+		null:  !n.Valid,
+	}
+	return nil
+}
+
+// Value for database. Absent and null values are NULL
+func (o Optional[T]) Value() (driver.Value, error) {
+	if !o.set {
+		return nil, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(o.value)
+}
@@ -0,0 +1,29 @@
+package ptr
+
+// Returns pointer to value
+//
+// Ex.: qb.Update("users").ColsWithParams(jvm.M{"deleted_at": ptr.Ptr(time.Now())})
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Returns value of pointer or default value if pointer is nil
+//
+// Ex.: limit := ptr.Deref(req.Limit, 20)
+func Deref[T any](p *T, defaultValue T) T {
+	if p == nil {
+		return defaultValue
+	}
+	return *p
+}
+
+// Returns pointer to value or nil if value is zero
+//
+// Ex.: ptr.NilIfZero(user.MiddleName)
+func NilIfZero[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,33 @@
+package qb
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Receives every executed query
+type QueryLogger interface {
+	// Called after query is executed. Err is the translated error of the query or nil
+	LogQuery(sql string, args []any, duration time.Duration, err error)
+}
+
+var queryLogger atomic.Pointer[QueryLogger]
+
+// Sets logger of executed queries for all builders. Nil disables logging
+//
+// Ex.: qb.SetQueryLogger(log.QueryLogger(logger, log.LevelDebug))
+func SetQueryLogger(l QueryLogger) {
+	if l == nil {
+		queryLogger.Store(nil)
+		return
+	}
+	queryLogger.Store(&l)
+}
+
+// Passes executed query to the logger and returns err
+func (b *builder) logQuery(start time.Time, err error) error {
+	if l := queryLogger.Load(); l != nil {
+		(*l).LogQuery(b.sql, b.params, time.Since(start), err)
+	}
+	return err
+}
@@ -2,8 +2,10 @@ package qb
 
 import (
 	"database/sql"
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	jve "github.com/jvnonce/jv-go-utils/lib/errors"
 	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
@@ -26,6 +28,8 @@ type builder struct {
 	where       string
 	joins       []string
 	orderBy     []string
+	keyset      string
+	err         error
 	groupBy     string
 	having      string
 	limit       int
@@ -104,11 +108,20 @@ type QueryBuilder interface {
 	// Ex.: qb.Select("users").Alias("u").RightJoin("profile", "p", "u.id=p.user_id")
 	RightJoin(tableName string, aliasName string, condition string) QueryBuilder
 
-	// Order results of select query
+	// Order results of select query. Multiple calls are joined in call order
 	//
-	// Ex.: qb.Select("users").OrderBy("name", "ASC")
+	// Ex.: qb.Select("users").OrderBy("name", "ASC").OrderBy("id", "ASC") // ORDER BY name ASC, id ASC
 	OrderBy(column string, direction string) QueryBuilder
 
+	// Keyset pagination of select query. Selects rows after the given values of columns
+	// in direction and orders by them before other orderings. Condition is combined with Where by AND.
+	// Columns must be unique together, so non-unique sort column needs a unique tie-breaker
+	// like primary key as the last column, otherwise rows sharing the boundary value are skipped.
+	// Empty after selects from the beginning, count of after values must match count of columns
+	//
+	// Ex.: qb.Select("posts").Keyset([]string{"created_at", "id"}, "DESC", []any{lastCreatedAt, lastID}).Limit(20)
+	Keyset(columns []string, direction string, after []any) QueryBuilder
+
 	// Grouping results of select query
 	//
 	// Ex.: qb.Select("users").Columns("id", "MAX(account) AS max_acc").GroupBy("id").Having("max_acc")
@@ -129,22 +142,33 @@ type QueryBuilder interface {
 	// Ex.: qb.Select("users").Limit(10).Offset(5)
 	Offset(offset int) QueryBuilder
 
-	// Executes query and returns first row
+	// Executes query and returns first row.
+	// Driver errors are translated by errors.FromPG
 	//
 	// Ex.: qb.Select("users").Where("id=?", 5).Row()
 	Row() (jvm.M, error)
 
-	// Executes query and returns slice of rows map column/value
+	// Executes query and returns slice of rows map column/value.
+	// Driver errors are translated by errors.FromPG
 	//
 	// Ex.: qb.Select("users").Where("id > ?", 5).Rows()
 	Rows() ([]jvm.M, error)
 
-	// Executes insert query and returns inserted row identificator with name colID
+	// Executes query and calls fn for every row without loading all rows into memory.
+	// Iteration stops on the first error of fn, which is returned.
+	// Driver errors are translated by errors.FromPG
+	//
+	// Ex.: qb.Select("orders").OrderBy("id", "ASC").EachRow(csvWriter.WriteRow)
+	EachRow(fn func(row jvm.M) error) error
+
+	// Executes insert query and returns inserted row identificator with name colID.
+	// Driver errors are translated by errors.FromPG
 	//
 	// Ex.: qb.Insert("users").Columns("name", "email").Parameters("jv", "jv19841202@gmail.com").ExecReturnID()
 	ExecReturnID(colID string) (interface{}, error)
 
-	// Executes insert or update query
+	// Executes insert or update query.
+	// Driver errors are translated by errors.FromPG
 	//
 	// Ex.: qb.Update("users").Columns("name", "email").Parameters("jv", "jv19841202@gmail.com").Where("name=?", "jv").Exec()
 	Exec() error
@@ -235,7 +259,37 @@ func (b *builder) RightJoin(tableName string, aliasName string, condition string
 	return b
 }
 func (b *builder) OrderBy(column string, direction string) QueryBuilder {
-	b.orderBy = append(b.orderBy, "ORDER BY "+column+" "+direction)
+	b.orderBy = append(b.orderBy, column+" "+direction)
+	return b
+}
+func (b *builder) Keyset(columns []string, direction string, after []any) QueryBuilder {
+	order := make([]string, len(columns))
+	for i, col := range columns {
+		order[i] = col + " " + direction
+	}
+	b.orderBy = append(order, b.orderBy...)
+	if len(after) == 0 {
+		return b
+	}
+	if len(after) != len(columns) {
+		b.err = fmt.Errorf("%w: keyset of %d columns with %d values", jve.ErrInvalid, len(columns), len(after))
+		return b
+	}
+	op := " > "
+	if strings.EqualFold(direction, "DESC") {
+		op = " < "
+	}
+	// (col1, col2) > ($1, $2)
+	placeholders := make([]string, len(after))
+	for i, value := range after {
+		b.params = append(b.params, value)
+		placeholders[i] = "$" + strconv.Itoa(len(b.params))
+	}
+	if len(columns) == 1 {
+		b.keyset = columns[0] + op + placeholders[0]
+	} else {
+		b.keyset = "(" + strings.Join(columns, ", ") + ")" + op + "(" + strings.Join(placeholders, ", ") + ")"
+	}
 	return b
 }
 func (b *builder) GroupBy(args ...string) QueryBuilder {
@@ -267,8 +321,9 @@ func (b *builder) Row() (jvm.M, error) {
 			return nil, err
 		}
 	}
+	start := time.Now()
 	rows, err := b.db.Query(b.sql, b.params...)
-	if err != nil {
+	if err = b.logQuery(start, jve.FromPG(err)); err != nil {
 		return nil, err
 	}
 	result := make(jvm.M)
@@ -295,36 +350,50 @@ func (b *builder) Row() (jvm.M, error) {
 }
 
 func (b *builder) Rows() ([]jvm.M, error) {
+	result := make([]jvm.M, 0)
+	err := b.EachRow(func(row jvm.M) error {
+		result = append(result, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (b *builder) EachRow(fn func(row jvm.M) error) error {
 	if !b.isManualSQL {
 		if err := b.buildQuery(); err != nil {
-			return nil, err
+			return err
 		}
 	}
+	start := time.Now()
 	rows, err := b.db.Query(b.sql, b.params...)
-	if err != nil {
-		return nil, err
+	if err = b.logQuery(start, jve.FromPG(err)); err != nil {
+		return err
 	}
-	result := make([]jvm.M, 0)
+	defer rows.Close()
 	columns, err := rows.Columns()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
 	for rows.Next() {
 		row := make([]interface{}, len(columns))
 		for i := range row {
 			row[i] = new(interface{})
 		}
 		if err := rows.Scan(row...); err != nil {
-			return nil, err
+			return err
 		}
-		rows := make(jvm.M)
+		result := make(jvm.M, len(columns))
 		for i, col := range columns {
-			rows[col] = *(row[i]).(*interface{})
+			result[col] = *(row[i]).(*interface{})
+		}
+		if err := fn(result); err != nil {
+			return err
 		}
-		result = append(result, rows)
 	}
-	return result, nil
+	return jve.FromPG(rows.Err())
 }
 
 func (b *builder) ExecReturnID(colID string) (interface{}, error) {
@@ -335,8 +404,9 @@ func (b *builder) ExecReturnID(colID string) (interface{}, error) {
 	}
 	b.sql += "\nRETURNING " + colID
 	lastInsertedID := new(interface{})
+	start := time.Now()
 	err := b.db.QueryRow(b.sql, b.params...).Scan(lastInsertedID)
-	return lastInsertedID, err
+	return lastInsertedID, b.logQuery(start, jve.FromPG(err))
 }
 
 func (b *builder) Exec() error {
@@ -345,11 +415,15 @@ func (b *builder) Exec() error {
 			return err
 		}
 	}
+	start := time.Now()
 	_, err := b.db.Exec(b.sql, b.params...)
-	return err
+	return b.logQuery(start, jve.FromPG(err))
 }
 
 func (b *builder) buildQuery() error {
+	if b.err != nil {
+		return b.err
+	}
 	switch b.action {
 	case selectAction:
 		return b.buildSelect()
@@ -385,8 +459,13 @@ func (b *builder) buildSelect() error {
 		}
 	}
 
-	if b.where != "" {
+	switch {
+	case b.where != "" && b.keyset != "":
+		b.sql += "\nWHERE (" + b.where + ") AND " + b.keyset
+	case b.where != "":
 		b.sql += "\nWHERE " + b.where
+	case b.keyset != "":
+		b.sql += "\nWHERE " + b.keyset
 	}
 
 	if b.groupBy != "" {
@@ -397,6 +476,10 @@ func (b *builder) buildSelect() error {
 		b.sql += "\n" + b.having
 	}
 
+	if len(b.orderBy) > 0 {
+		b.sql += "\nORDER BY " + strings.Join(b.orderBy, ", ")
+	}
+
 	if b.offset > 0 {
 		b.sql += "\nOFFSET " + strconv.Itoa(b.offset)
 	}
@@ -0,0 +1,70 @@
+package qb
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+)
+
+func TestKeyset(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		query  QueryBuilder
+		sql    string
+		params []any
+	}{
+		{
+			name:   "first page",
+			query:  New(nil).Select("posts").Keyset([]string{"id"}, "ASC", nil).Limit(3),
+			sql:    "SELECT\n*\nFROM posts\nORDER BY id ASC\nLIMIT 3",
+			params: []any{},
+		},
+		{
+			name:   "ascending",
+			query:  New(nil).Select("posts").Keyset([]string{"id"}, "ASC", []any{5}).Limit(3),
+			sql:    "SELECT\n*\nFROM posts\nWHERE id > $1\nORDER BY id ASC\nLIMIT 3",
+			params: []any{5},
+		},
+		{
+			name:   "descending",
+			query:  New(nil).Select("posts").Keyset([]string{"id"}, "DESC", []any{5}),
+			sql:    "SELECT\n*\nFROM posts\nWHERE id < $1\nORDER BY id DESC",
+			params: []any{5},
+		},
+		{
+			name: "tie-breaker with where and order",
+			query: New(nil).Select("posts").Where("author = ?", "jv").
+				Keyset([]string{"created_at", "id"}, "DESC", []any{"2024-05-01", 7}).OrderBy("title", "ASC"),
+			sql:    "SELECT\n*\nFROM posts\nWHERE (author = $1) AND (created_at, id) < ($2, $3)\nORDER BY created_at DESC, id DESC, title ASC",
+			params: []any{"jv", "2024-05-01", 7},
+		},
+		{
+			name:   "tie-breaker ascending",
+			query:  New(nil).Select("posts").Keyset([]string{"name", "id"}, "ASC", []any{"a", 1}),
+			sql:    "SELECT\n*\nFROM posts\nWHERE (name, id) > ($1, $2)\nORDER BY name ASC, id ASC",
+			params: []any{"a", 1},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.query.(*builder)
+			if err := b.buildQuery(); err != nil {
+				t.Fatal(err)
+			}
+			if b.sql != tt.sql {
+				t.Errorf("sql = %q, want %q", b.sql, tt.sql)
+			}
+			if !reflect.DeepEqual(b.params, tt.params) {
+				t.Errorf("params = %v, want %v", b.params, tt.params)
+			}
+		})
+	}
+}
+
+func TestKeysetValuesMismatch(t *testing.T) {
+	b := New(nil).Select("posts").Keyset([]string{"created_at", "id"}, "ASC", []any{1}).(*builder)
+	if err := b.buildQuery(); !errors.Is(err, jve.ErrInvalid) {
+		t.Fatalf("error = %v, want ErrInvalid", err)
+	}
+}
@@ -0,0 +1,166 @@
+package queue
+
+import "context"
+
+// Thread-safe double-ended queue with blocking pops
+type Deque[T any] interface {
+	// Adds values to the front. The last value becomes the first
+	PushFront(values ...T)
+	// Adds values to the back
+	PushBack(values ...T)
+	// Removes and returns the first value waiting until deque is not empty or context is done
+	PopFront(ctx context.Context) (T, error)
+	// Removes and returns the last value waiting until deque is not empty or context is done
+	PopBack(ctx context.Context) (T, error)
+	// Removes and returns the first value without waiting
+	TryPopFront() (T, bool)
+	// Removes and returns the last value without waiting
+	TryPopBack() (T, bool)
+	// Returns the first value without removing it
+	PeekFront() (T, bool)
+	// Returns the last value without removing it
+	PeekBack() (T, bool)
+	// Count of values
+	Len() int
+}
+
+type deque[T any] struct {
+	waitable
+	items ring[T]
+}
+
+// Ring buffer growing on demand
+type ring[T any] struct {
+	buf  []T
+	head int
+	len  int
+}
+
+// Deque constructor
+//
+// Ex.: d := queue.NewDeque[int](); d.PushBack(1, 2); d.PushFront(0)
+func NewDeque[T any]() Deque[T] {
+	return &deque[T]{}
+}
+
+func (d *deque[T]) PushFront(values ...T) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, v := range values {
+		d.items.pushFront(v)
+	}
+	d.signal()
+}
+
+func (d *deque[T]) PushBack(values ...T) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, v := range values {
+		d.items.pushBack(v)
+	}
+	d.signal()
+}
+
+func (d *deque[T]) PopFront(ctx context.Context) (T, error) {
+	return pop(ctx, &d.waitable, d.items.popFront)
+}
+
+func (d *deque[T]) PopBack(ctx context.Context) (T, error) {
+	return pop(ctx, &d.waitable, d.items.popBack)
+}
+
+func (d *deque[T]) TryPopFront() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.items.popFront()
+}
+
+func (d *deque[T]) TryPopBack() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.items.popBack()
+}
+
+func (d *deque[T]) PeekFront() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.items.front()
+}
+
+func (d *deque[T]) PeekBack() (T, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.items.back()
+}
+
+func (d *deque[T]) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.items.len
+}
+
+func (r *ring[T]) pushBack(v T) {
+	r.grow()
+	r.buf[(r.head+r.len)%len(r.buf)] = v
+	r.len++
+}
+
+func (r *ring[T]) pushFront(v T) {
+	r.grow()
+	r.head = (r.head - 1 + len(r.buf)) % len(r.buf)
+	r.buf[r.head] = v
+	r.len++
+}
+
+func (r *ring[T]) popFront() (T, bool) {
+	var zero T
+	if r.len == 0 {
+		return zero, false
+	}
+	v := r.buf[r.head]
+	r.buf[r.head] = zero
+	r.head = (r.head + 1) % len(r.buf)
+	r.len--
+	return v, true
+}
+
+func (r *ring[T]) popBack() (T, bool) {
+	var zero T
+	if r.len == 0 {
+		return zero, false
+	}
+	i := (r.head + r.len - 1) % len(r.buf)
+	v := r.buf[i]
+	r.buf[i] = zero
+	r.len--
+	return v, true
+}
+
+func (r *ring[T]) front() (T, bool) {
+	if r.len == 0 {
+		var zero T
+		return zero, false
+	}
+	return r.buf[r.head], true
+}
+
+func (r *ring[T]) back() (T, bool) {
+	if r.len == 0 {
+		var zero T
+		return zero, false
+	}
+	return r.buf[(r.head+r.len-1)%len(r.buf)], true
+}
+
+// Doubles buffer if it is full
+func (r *ring[T]) grow() {
+	if r.len < len(r.buf) {
+		return
+	}
+	buf := make([]T, max(2*len(r.buf), 8))
+	for i := 0; i < r.len; i++ {
+		buf[i] = r.buf[(r.head+i)%len(r.buf)]
+	}
+	r.buf = buf
+	r.head = 0
+}
@@ -0,0 +1,96 @@
+package queue
+
+import "context"
+
+type priority[T any] struct {
+	waitable
+	items []T
+	cmp   func(a, b T) int
+}
+
+// Priority queue constructor. Value with the lowest order by cmp is popped first,
+// order of equal values is not preserved. Comparator of chains package may be used as cmp
+//
+// Ex.: q := queue.NewPriority(chains.By(func(t Task) int { return t.Priority }).Desc())
+func NewPriority[T any](cmp func(a, b T) int) Queue[T] {
+	return &priority[T]{cmp: cmp}
+}
+
+func (q *priority[T]) Push(values ...T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, v := range values {
+		q.items = append(q.items, v)
+		q.up(len(q.items) - 1)
+	}
+	q.signal()
+}
+
+func (q *priority[T]) Pop(ctx context.Context) (T, error) {
+	return pop(ctx, &q.waitable, q.take)
+}
+
+func (q *priority[T]) TryPop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.take()
+}
+
+func (q *priority[T]) Peek() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.items[0], true
+}
+
+func (q *priority[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Removes the root of heap. Must be called with locked mutex
+func (q *priority[T]) take() (T, bool) {
+	var zero T
+	n := len(q.items)
+	if n == 0 {
+		return zero, false
+	}
+	v := q.items[0]
+	q.items[0] = q.items[n-1]
+	q.items[n-1] = zero
+	q.items = q.items[:n-1]
+	q.down(0)
+	return v, true
+}
+
+func (q *priority[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if q.cmp(q.items[i], q.items[parent]) >= 0 {
+			return
+		}
+		q.items[i], q.items[parent] = q.items[parent], q.items[i]
+		i = parent
+	}
+}
+
+func (q *priority[T]) down(i int) {
+	n := len(q.items)
+	for {
+		smallest := i
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child < n && q.cmp(q.items[child], q.items[smallest]) < 0 {
+				smallest = child
+			}
+		}
+		if smallest == i {
+			return
+		}
+		q.items[i], q.items[smallest] = q.items[smallest], q.items[i]
+		i = smallest
+	}
+}
@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"context"
+	"sync"
+)
+
+// Thread-safe queue with blocking Pop
+type Queue[T any] interface {
+	// Adds values to queue
+	Push(values ...T)
+	// Removes and returns the next value waiting until queue is not empty or context is done
+	//
+	// Ex.: job, err := q.Pop(ctx)
+	Pop(ctx context.Context) (T, error)
+	// Removes and returns the next value without waiting. Returns false if queue is empty
+	TryPop() (T, bool)
+	// Returns the next value without removing it
+	Peek() (T, bool)
+	// Count of values
+	Len() int
+}
+
+// Base of queues. Pop waits for channel which is closed on every push
+type waitable struct {
+	mu   sync.Mutex
+	wait chan struct{}
+}
+
+type fifo[T any] struct {
+	waitable
+	items ring[T]
+}
+
+// FIFO queue constructor
+//
+// Ex.: q := queue.New[Job](); q.Push(job)
+func New[T any]() Queue[T] {
+	return &fifo[T]{}
+}
+
+func (q *fifo[T]) Push(values ...T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, v := range values {
+		q.items.pushBack(v)
+	}
+	q.signal()
+}
+
+func (q *fifo[T]) Pop(ctx context.Context) (T, error) {
+	return pop(ctx, &q.waitable, q.items.popFront)
+}
+
+func (q *fifo[T]) TryPop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.popFront()
+}
+
+func (q *fifo[T]) Peek() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.front()
+}
+
+func (q *fifo[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.len
+}
+
+// Wakes waiting Pop calls. Must be called with locked mutex
+func (w *waitable) signal() {
+	if w.wait != nil {
+		close(w.wait)
+		w.wait = nil
+	}
+}
+
+// Takes value by take waiting for signal while it returns false. Take is called with locked mutex
+func pop[T any](ctx context.Context, w *waitable, take func() (T, bool)) (T, error) {
+	for {
+		w.mu.Lock()
+		if v, ok := take(); ok {
+			w.mu.Unlock()
+			return v, nil
+		}
+		if w.wait == nil {
+			w.wait = make(chan struct{})
+		}
+		wait := w.wait
+		w.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+	"github.com/jvnonce/jv-go-utils/lib/log"
+)
+
+// Limit of token bucket: Rate tokens are added per second up to Burst tokens
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+type limiter[K comparable] struct {
+	mu        sync.Mutex
+	limit     Limit
+	overrides map[K]Limit
+	buckets   map[K]*bucket
+	store     Store[K]
+	idle      time.Duration
+	done      chan struct{}
+	once      sync.Once
+}
+
+// Option of limiter
+type Option[K comparable] func(*limiter[K])
+
+// Token bucket rate limiter keyed by K. Safe for concurrent use
+type Limiter[K comparable] interface {
+	// Takes one token of key. Returns false if there are no tokens.
+	// Errors of store are logged and request is allowed
+	//
+	// Ex.: if !limiter.Allow(r.RemoteAddr) { w.WriteHeader(http.StatusTooManyRequests); return }
+	Allow(key K) bool
+	// Takes n tokens of key. Returns false if there are not enough tokens
+	AllowN(key K, n int) bool
+	// Waits for one token of key. Returns context error if context is done first
+	Wait(ctx context.Context, key K) error
+	// Waits for n tokens of key
+	WaitN(ctx context.Context, key K, n int) error
+	// Sets limit of key instead of default one
+	SetLimit(key K, limit Limit)
+	// Count of keys with local buckets
+	Len() int
+	// Stops eviction of idle keys
+	Close()
+}
+
+// Limit of burst tokens refilled one per interval
+//
+// Ex.: ratelimit.Every(100*time.Millisecond, 20) // 10 requests per second with bursts of 20
+func Every(interval time.Duration, burst int) Limit {
+	return Limit{
+		Rate:  float64(time.Second) / float64(interval),
+		Burst: burst,
+	}
+}
+
+// Limiter constructor
+//
+// Ex.: ratelimit.New[string](ratelimit.Limit{Rate: 5, Burst: 10}, ratelimit.WithIdleTimeout[string](10*time.Minute))
+func New[K comparable](limit Limit, opts ...Option[K]) Limiter[K] {
+	l := &limiter[K]{
+		limit:     limit,
+		overrides: make(map[K]Limit),
+		buckets:   make(map[K]*bucket),
+		done:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.idle > 0 {
+		go l.evict()
+	}
+	return l
+}
+
+// Removes buckets of keys not used for timeout. Limiter must be closed to stop eviction
+func WithIdleTimeout[K comparable](timeout time.Duration) Option[K] {
+	return func(l *limiter[K]) {
+		l.idle = timeout
+	}
+}
+
+// Keeps buckets in the store shared by several processes instead of memory
+//
+// Ex.: ratelimit.New(limit, ratelimit.WithStore(ratelimit.NewPGStore[string](db, "rate_limits")))
+func WithStore[K comparable](store Store[K]) Option[K] {
+	return func(l *limiter[K]) {
+		l.store = store
+	}
+}
+
+func (l *limiter[K]) Allow(key K) bool {
+	return l.AllowN(key, 1)
+}
+
+func (l *limiter[K]) AllowN(key K, n int) bool {
+	limit := l.limitOf(key)
+	if l.store != nil {
+		ok, _, err := l.store.Take(context.Background(), key, limit, n)
+		if err != nil {
+			log.Error("rate limit store failed", log.Err(err))
+			return true
+		}
+		return ok
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.bucket(key, limit)
+	b.refill(time.Now())
+	if b.tokens < float64(n) {
+		return false
+	}
+	b.tokens -= float64(n)
+	return true
+}
+
+func (l *limiter[K]) Wait(ctx context.Context, key K) error {
+	return l.WaitN(ctx, key, 1)
+}
+
+func (l *limiter[K]) WaitN(ctx context.Context, key K, n int) error {
+	limit := l.limitOf(key)
+	if n > limit.Burst || limit.Rate <= 0 {
+		return fmt.Errorf("%w: %d tokens exceed limit", jve.ErrInvalid, n)
+	}
+	if l.store != nil {
+		return l.waitStore(ctx, key, limit, n)
+	}
+
+	// tokens are reserved in advance, so waiters are served in order of arrival
+	l.mu.Lock()
+	b := l.bucket(key, limit)
+	b.refill(time.Now())
+	b.tokens -= float64(n)
+	delay := b.delay()
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		if b, ok := l.buckets[key]; ok {
+			b.tokens += float64(n)
+		}
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func (l *limiter[K]) SetLimit(key K, limit Limit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.overrides[key] = limit
+	if b, ok := l.buckets[key]; ok {
+		b.refill(time.Now())
+		b.limit = limit
+		b.tokens = min(b.tokens, float64(limit.Burst))
+	}
+}
+
+func (l *limiter[K]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
+
+func (l *limiter[K]) Close() {
+	l.once.Do(func() {
+		close(l.done)
+	})
+}
+
+func (l *limiter[K]) limitOf(key K) Limit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if limit, ok := l.overrides[key]; ok {
+		return limit
+	}
+	return l.limit
+}
+
+// Returns bucket of key creating full one. Must be called with locked mutex
+func (l *limiter[K]) bucket(key K, limit Limit) *bucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{
+			limit:  limit,
+			tokens: float64(limit.Burst),
+			last:   time.Now(),
+		}
+		l.buckets[key] = b
+	}
+	return b
+}
+
+func (l *limiter[K]) waitStore(ctx context.Context, key K, limit Limit, n int) error {
+	for {
+		ok, delay, err := l.store.Take(ctx, key, limit, n)
+		if err != nil || ok {
+			return err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+func (l *limiter[K]) evict() {
+	t := time.NewTicker(l.idle)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			now := time.Now()
+			l.mu.Lock()
+			for key, b := range l.buckets {
+				// bucket with reserved tokens is kept until waiters get them
+				if now.Sub(b.last) >= l.idle && b.tokens >= 0 {
+					delete(l.buckets, key)
+				}
+			}
+			l.mu.Unlock()
+		case <-l.done:
+			return
+		}
+	}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(float64(b.limit.Burst), b.tokens+elapsed.Seconds()*b.limit.Rate)
+	}
+	b.last = now
+}
+
+// Time until tokens are not negative
+func (b *bucket) delay() time.Duration {
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.limit.Rate * float64(time.Second))
+}
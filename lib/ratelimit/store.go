@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+	"github.com/jvnonce/jv-go-utils/lib/qb"
+)
+
+// Shared storage of token buckets
+type Store[K comparable] interface {
+	// Takes n tokens of key. Returns false and approximate time to wait if there are not enough tokens
+	Take(ctx context.Context, key K, limit Limit, n int) (bool, time.Duration, error)
+}
+
+type pgStore[K comparable] struct {
+	db    *sql.DB
+	table string
+}
+
+// PostgreSQL store constructor. Bucket is updated by one atomic statement,
+// so limit is shared by all processes using the table.
+// Type of key column must accept K
+//
+// Ex.: CREATE TABLE rate_limits (key text PRIMARY KEY, tokens double precision NOT NULL, updated_at timestamptz NOT NULL)
+func NewPGStore[K comparable](db *sql.DB, table string) Store[K] {
+	return &pgStore[K]{
+		db:    db,
+		table: table,
+	}
+}
+
+func (s *pgStore[K]) Take(ctx context.Context, key K, limit Limit, n int) (bool, time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return false, 0, err
+	}
+	if n > limit.Burst {
+		return false, 0, jve.ErrInvalid
+	}
+	refilled := "LEAST(?::float8, t.tokens + EXTRACT(EPOCH FROM now() - t.updated_at) * ?::float8)"
+	_, err := qb.New(s.db).SQL(
+		"INSERT INTO "+s.table+" AS t (key, tokens, updated_at) VALUES (?, ?::float8, now())\n"+
+			"ON CONFLICT (key) DO UPDATE SET tokens = "+refilled+" - ?::float8, updated_at = now()\n"+
+			"WHERE "+refilled+" >= ?::float8\n"+
+			"RETURNING tokens",
+		key, limit.Burst-n,
+		limit.Burst, limit.Rate, n,
+		limit.Burst, limit.Rate, n,
+	).Row()
+	if errors.Is(err, jve.ErrNotFound) {
+		if limit.Rate <= 0 {
+			return false, 0, nil
+		}
+		return false, time.Duration(float64(n) / limit.Rate * float64(time.Second)), nil
+	}
+	return err == nil, 0, err
+}
@@ -0,0 +1,181 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+)
+
+const (
+	defaultAttempts = 3
+	// Base delay of the policy used for nil policy
+	defaultBase = 100 * time.Millisecond
+)
+
+type policy struct {
+	delay       func(attempt int) time.Duration
+	maxAttempts int
+	maxDelay    time.Duration
+	jitter      float64
+	classify    func(err error) bool
+	onRetry     func(attempt int, err error, delay time.Duration)
+}
+
+// Retry policy. Methods configure and return the same policy
+type Policy interface {
+	// Maximum count of attempts including the first one. Zero or negative means no limit.
+	// Default is 3
+	MaxAttempts(n int) Policy
+
+	// Upper limit of delay between attempts
+	MaxDelay(d time.Duration) Policy
+
+	// Randomizes delay by fraction in both directions
+	//
+	// Ex.: retry.Exponential(time.Second).Jitter(0.2) waits from 0.8 to 1.2 seconds after the first attempt
+	Jitter(fraction float64) Policy
+
+	// Sets hook deciding whether error should be retried. Default hook is Retryable
+	Classify(fn func(err error) bool) Policy
+
+	// Sets callback called before waiting for the next attempt
+	OnRetry(fn func(attempt int, err error, delay time.Duration)) Policy
+
+	// Returns delay after the attempt
+	Delay(attempt int) time.Duration
+}
+
+// Policy with the same delay between attempts
+func Constant(d time.Duration) Policy {
+	return newPolicy(func(int) time.Duration {
+		return d
+	})
+}
+
+// Policy with delay growing by step after every attempt
+func Linear(step time.Duration) Policy {
+	return newPolicy(func(attempt int) time.Duration {
+		return step * time.Duration(attempt)
+	})
+}
+
+// Policy with delay doubling after every attempt
+//
+// Ex.: retry.Exponential(100*time.Millisecond).MaxAttempts(5).Jitter(0.2)
+func Exponential(base time.Duration) Policy {
+	return newPolicy(func(attempt int) time.Duration {
+		d := float64(base) * math.Pow(2, float64(attempt-1))
+		if d > math.MaxInt64 {
+			return time.Duration(math.MaxInt64)
+		}
+		return time.Duration(d)
+	})
+}
+
+// Default classification. Errors marked as retryable and internal errors are retried,
+// errors with other codes of errors package, like not found or invalid, are not.
+// Context errors are never retried
+func Retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return jve.IsRetryable(err) || jve.Code(err) == jve.CodeInternal
+}
+
+// Calls fn until it succeeds, returns not retryable error or attempts are exhausted.
+// The last error is returned. When context is done during waiting,
+// context error is returned together with the last error.
+// Nil policy is Exponential(100*time.Millisecond) with default attempts
+//
+// Ex.: err := retry.Do(ctx, func(ctx context.Context) error { return send(ctx, msg) }, retry.Exponential(time.Second))
+func Do(ctx context.Context, fn func(ctx context.Context) error, p Policy) error {
+	_, err := DoValue(ctx, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, p)
+	return err
+}
+
+// Calls fn like Do and returns its value
+func DoValue[T any](ctx context.Context, fn func(ctx context.Context) (T, error), p Policy) (T, error) {
+	var pol *policy
+	switch x := p.(type) {
+	case nil:
+	case *policy:
+		pol = x
+	default:
+		pol = newPolicy(p.Delay)
+	}
+	if pol == nil {
+		pol = Exponential(defaultBase).(*policy)
+	}
+	for attempt := 1; ; attempt++ {
+		v, err := fn(ctx)
+		if err == nil {
+			return v, nil
+		}
+		if !pol.classify(err) || (pol.maxAttempts > 0 && attempt >= pol.maxAttempts) {
+			return v, err
+		}
+
+		delay := pol.Delay(attempt)
+		if pol.onRetry != nil {
+			pol.onRetry(attempt, err, delay)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return v, fmt.Errorf("%w: %w", ctx.Err(), err)
+		}
+	}
+}
+
+func newPolicy(delay func(attempt int) time.Duration) *policy {
+	return &policy{
+		delay:       delay,
+		maxAttempts: defaultAttempts,
+		classify:    Retryable,
+	}
+}
+
+func (p *policy) MaxAttempts(n int) Policy {
+	p.maxAttempts = n
+	return p
+}
+
+func (p *policy) MaxDelay(d time.Duration) Policy {
+	p.maxDelay = d
+	return p
+}
+
+func (p *policy) Jitter(fraction float64) Policy {
+	p.jitter = fraction
+	return p
+}
+
+func (p *policy) Classify(fn func(err error) bool) Policy {
+	p.classify = fn
+	return p
+}
+
+func (p *policy) OnRetry(fn func(attempt int, err error, delay time.Duration)) Policy {
+	p.onRetry = fn
+	return p
+}
+
+func (p *policy) Delay(attempt int) time.Duration {
+	d := p.delay(attempt)
+	if p.maxDelay > 0 && d > p.maxDelay {
+		d = p.maxDelay
+	}
+	if p.jitter > 0 {
+		d = time.Duration(float64(d) * (1 + p.jitter*(2*rand.Float64()-1)))
+	}
+	return max(d, 0)
+}
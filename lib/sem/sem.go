@@ -0,0 +1,155 @@
+package sem
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+)
+
+type waiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+type semaphore struct {
+	mu      sync.Mutex
+	size    int64
+	cur     int64
+	waiters list.List
+}
+
+// Weighted semaphore. Waiters are served in order of arrival,
+// so large requests are not starved by small ones. Safe for concurrent use
+type Semaphore interface {
+	// Acquires n units waiting until they are available or context is done.
+	// Returns error with invalid code if n exceeds size
+	//
+	// Ex.: if err := s.Acquire(ctx, 1); err != nil { return err }; defer s.Release(1)
+	Acquire(ctx context.Context, n int64) error
+	// Acquires n units without waiting. Returns false if they are not available
+	TryAcquire(n int64) bool
+	// Releases n units
+	Release(n int64)
+}
+
+// Semaphore constructor
+//
+// Ex.: s := sem.New(10)
+func New(size int64) Semaphore {
+	return &semaphore{size: size}
+}
+
+// Runs tasks with at most n of them at once. The first error cancels context of other tasks
+// and is returned after all started tasks finish
+//
+// Ex.: err := sem.Limit(ctx, 4, []func(context.Context) error{fetchUsers, fetchOrders, fetchItems})
+func Limit(ctx context.Context, n int, tasks []func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s := New(int64(max(n, 1)))
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	for _, task := range tasks {
+		if err := s.Acquire(ctx, 1); err != nil {
+			fail(err)
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer s.Release(1)
+			if err := jve.Safe(func() error { return task(ctx) }); err != nil {
+				fail(err)
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+func (s *semaphore) Acquire(ctx context.Context, n int64) error {
+	if n > s.size {
+		return fmt.Errorf("%w: %d units exceed semaphore size %d", jve.ErrInvalid, n, s.size)
+	}
+	s.mu.Lock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+		return nil
+	}
+	w := waiter{n: n, ready: make(chan struct{})}
+	el := s.waiters.PushBack(w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		select {
+		case <-w.ready:
+			// acquired after context was done, give units back
+			s.cur -= n
+			s.notify()
+		default:
+			front := s.waiters.Front() == el
+			s.waiters.Remove(el)
+			// waiters behind the removed front one may fit now
+			if front {
+				s.notify()
+			}
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func (s *semaphore) TryAcquire(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		return true
+	}
+	return false
+}
+
+func (s *semaphore) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cur -= n
+	if s.cur < 0 {
+		panic("sem: released more than held")
+	}
+	s.notify()
+}
+
+// Wakes waiters from the front while they fit. Must be called with locked mutex
+func (s *semaphore) notify() {
+	for {
+		el := s.waiters.Front()
+		if el == nil {
+			return
+		}
+		w := el.Value.(waiter)
+		if s.size-s.cur < w.n {
+			return
+		}
+		s.cur += w.n
+		s.waiters.Remove(el)
+		close(w.ready)
+	}
+}
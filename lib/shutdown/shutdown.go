@@ -0,0 +1,163 @@
+package shutdown
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+)
+
+// Default timeout of every hook
+const DefaultTimeout = 10 * time.Second
+
+// Shutdown hook. Context is done when timeout of the hook expires
+type Hook func(ctx context.Context) error
+
+type hook struct {
+	name    string
+	fn      Hook
+	timeout time.Duration
+}
+
+type config struct {
+	signals []os.Signal
+	timeout time.Duration
+}
+
+type shutdown struct {
+	config
+	mu     sync.Mutex
+	hooks  []hook
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+	err    error
+}
+
+// Option of shutdown
+type Option func(*config)
+
+// Orchestrator of graceful shutdown. Safe for concurrent use
+//
+// Ex.: s := shutdown.New(); s.Add("http", 0, srv.Shutdown); s.Add("db", time.Second, shutdown.Closer(db)); return s.Wait()
+type Shutdown interface {
+	// Registers hook. Hooks run in reverse order of registration, so resources
+	// are released before their dependencies. Zero timeout means default one
+	Add(name string, timeout time.Duration, fn Hook)
+	// Context which is done when shutdown starts. Intended for background workers
+	Context() context.Context
+	// Waits for signal or Shutdown call and returns result of hooks
+	Wait() error
+	// Starts shutdown and runs hooks. Hooks run once, later calls return the same result.
+	// Errors of hooks are joined into errors.MultiError
+	Shutdown() error
+}
+
+// Shutdown constructor. Listens for SIGINT and SIGTERM by default
+func New(opts ...Option) Shutdown {
+	s := &shutdown{
+		config: config{
+			signals: []os.Signal{os.Interrupt, syscall.SIGTERM},
+			timeout: DefaultTimeout,
+		},
+	}
+	for _, opt := range opts {
+		opt(&s.config)
+	}
+	s.ctx, s.cancel = signal.NotifyContext(context.Background(), s.signals...)
+	return s
+}
+
+// Signals starting shutdown
+func WithSignals(signals ...os.Signal) Option {
+	return func(c *config) {
+		c.signals = signals
+	}
+}
+
+// Default timeout of hooks
+func WithTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.timeout = d
+	}
+}
+
+// Hook of function without context and error
+//
+// Ex.: s.Add("tickers", 0, shutdown.Func(manager.StopAll))
+func Func(fn func()) Hook {
+	return func(context.Context) error {
+		fn()
+		return nil
+	}
+}
+
+// Hook closing closer
+//
+// Ex.: s.Add("db", 0, shutdown.Closer(db))
+func Closer(c io.Closer) Hook {
+	return func(context.Context) error {
+		return c.Close()
+	}
+}
+
+func (s *shutdown) Add(name string, timeout time.Duration, fn Hook) {
+	if timeout <= 0 {
+		timeout = s.timeout
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, hook{
+		name:    name,
+		fn:      fn,
+		timeout: timeout,
+	})
+}
+
+func (s *shutdown) Context() context.Context {
+	return s.ctx
+}
+
+func (s *shutdown) Wait() error {
+	<-s.ctx.Done()
+	return s.Shutdown()
+}
+
+func (s *shutdown) Shutdown() error {
+	s.once.Do(func() {
+		s.cancel()
+		s.mu.Lock()
+		hooks := append([]hook(nil), s.hooks...)
+		s.mu.Unlock()
+
+		var g jve.Group
+		for i := len(hooks) - 1; i >= 0; i-- {
+			g.Add(jve.Wrap(hooks[i].run(), hooks[i].name))
+		}
+		s.err = g.Err()
+	})
+	return s.err
+}
+
+// Runs hook with timeout. Hook ignoring context is abandoned when timeout expires
+func (h hook) run() error {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- jve.Safe(func() error {
+			return h.fn(ctx)
+		})
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,148 @@
+package strutil
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/iancoleman/strcase"
+)
+
+// Default alphabet of RandomString
+const Alphanumeric = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// Cuts string to n runes. Cut string ends with ellipsis which is counted in n
+//
+// Ex.: strutil.Truncate("Hello, world", 8) // "Hello, …"
+func Truncate(s string, n int) string {
+	return TruncateWith(s, n, "…")
+}
+
+// Cuts string to n runes ending with the given ellipsis
+//
+// Ex.: strutil.TruncateWith("Hello, world", 8, "...") // "Hello..."
+func TruncateWith(s string, n int, ellipsis string) string {
+	if n <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	keep := n - utf8.RuneCountInString(ellipsis)
+	if keep <= 0 {
+		return string(runes[:n])
+	}
+	return string(runes[:keep]) + ellipsis
+}
+
+// Converts string into lower case slug of letters and digits separated by hyphens.
+// Letters of all scripts are kept
+//
+// Ex.: strutil.Slugify("Hello, World! 2024") // "hello-world-2024"
+func Slugify(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+			continue
+		}
+		hyphen = true
+	}
+	return b.String()
+}
+
+// Returns cryptographically secure random string of n alphanumeric characters
+//
+// Ex.: token := strutil.RandomString(32)
+func RandomString(n int) string {
+	return RandomStringFrom(n, Alphanumeric)
+}
+
+// Returns cryptographically secure random string of n runes of alphabet
+//
+// Ex.: code := strutil.RandomStringFrom(6, "0123456789")
+func RandomStringFrom(n int, alphabet string) string {
+	runes := []rune(alphabet)
+	if n <= 0 || len(runes) == 0 {
+		return ""
+	}
+	max := big.NewInt(int64(len(runes)))
+	result := make([]rune, n)
+	for i := range result {
+		idx, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+		result[i] = runes[idx.Int64()]
+	}
+	return string(result)
+}
+
+// Pads string on the left with pad rune up to n runes
+//
+// Ex.: strutil.PadLeft("42", 5, '0') // "00042"
+func PadLeft(s string, n int, pad rune) string {
+	if count := n - utf8.RuneCountInString(s); count > 0 {
+		return strings.Repeat(string(pad), count) + s
+	}
+	return s
+}
+
+// Pads string on the right with pad rune up to n runes
+//
+// Ex.: strutil.PadRight("id", 5, '.') // "id..."
+func PadRight(s string, n int, pad rune) string {
+	if count := n - utf8.RuneCountInString(s); count > 0 {
+		return s + strings.Repeat(string(pad), count)
+	}
+	return s
+}
+
+// Converts string into lower camel case like maps.M.CamelKeys
+//
+// Ex.: strutil.CamelCase("user_id") // "userId"
+func CamelCase(s string) string {
+	return strcase.ToLowerCamel(s)
+}
+
+// Converts string into upper camel case
+//
+// Ex.: strutil.PascalCase("user_id") // "UserId"
+func PascalCase(s string) string {
+	return strcase.ToCamel(s)
+}
+
+// Converts string into snake case like maps.M.SnakeKeys
+//
+// Ex.: strutil.SnakeCase("userId") // "user_id"
+func SnakeCase(s string) string {
+	return strcase.ToSnake(s)
+}
+
+// Converts string into kebab case
+//
+// Ex.: strutil.KebabCase("userId") // "user-id"
+func KebabCase(s string) string {
+	return strcase.ToKebab(s)
+}
+
+// Hides string for logs keeping last visible runes. String not longer than
+// twice of visible is masked completely
+//
+// Ex.: strutil.Mask("4111111111111111", 4) // "************1111"
+func Mask(s string, visible int) string {
+	runes := []rune(s)
+	if visible < 0 || len(runes) <= visible*2 {
+		visible = 0
+	}
+	masked := len(runes) - visible
+	return strings.Repeat("*", masked) + string(runes[masked:])
+}
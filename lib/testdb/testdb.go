@@ -0,0 +1,182 @@
+// Package testdb connects tests to an existing database. Provisioning of the database
+// (containers, dockertest, CI services) is out of scope: it must be started outside of tests
+// and passed by TEST_DATABASE_URL, otherwise tests using it are skipped
+package testdb
+
+import (
+	"database/sql"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jvnonce/jv-go-utils/lib/env"
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+	"github.com/jvnonce/jv-go-utils/lib/qb"
+)
+
+type config struct {
+	driver  string
+	dsn     string
+	schema  []schemaFile
+	keep    []string
+	noClean bool
+}
+
+type schemaFile struct {
+	fsys fs.FS
+	path string
+}
+
+// Option of test database
+type Option func(*config)
+
+// Schemas applied once per process keyed by DSN and file
+var (
+	appliedMu sync.Mutex
+	applied   = make(map[string]bool)
+)
+
+// Connects to test database, applies schema files and truncates all tables.
+// DSN is read from TEST_DATABASE_URL and driver name from TEST_DATABASE_DRIVER (postgres by default),
+// driver must be imported by the test. Test is skipped if DSN is not set.
+// Connection is closed when test finishes
+//
+// Ex.: db := testdb.Open(t, testdb.WithSchema("../../schema.sql")); repo := NewRepo(qb.New(db))
+func Open(t testing.TB, opts ...Option) *sql.DB {
+	t.Helper()
+	c := config{
+		driver: env.String("TEST_DATABASE_DRIVER", "postgres"),
+		dsn:    env.String("TEST_DATABASE_URL", ""),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open(c.driver, c.dsn)
+	if err != nil {
+		t.Fatalf("testdb: open: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+	if err := db.Ping(); err != nil {
+		t.Fatalf("testdb: connect: %v", err)
+	}
+	for _, f := range c.schema {
+		applySchema(t, db, c.dsn, f)
+	}
+	if !c.noClean {
+		Truncate(t, db, c.keep...)
+	}
+	return db
+}
+
+// Uses DSN instead of TEST_DATABASE_URL
+func WithDSN(driver string, dsn string) Option {
+	return func(c *config) {
+		c.driver = driver
+		c.dsn = dsn
+	}
+}
+
+// Applies SQL files once per process. Files are applied in order
+func WithSchema(paths ...string) Option {
+	return func(c *config) {
+		for _, path := range paths {
+			c.schema = append(c.schema, schemaFile{path: path})
+		}
+	}
+}
+
+// Applies SQL files of file system once per process
+//
+// Ex.: testdb.WithSchemaFS(migrations.FS, "001_init.sql", "002_orders.sql")
+func WithSchemaFS(fsys fs.FS, paths ...string) Option {
+	return func(c *config) {
+		for _, path := range paths {
+			c.schema = append(c.schema, schemaFile{fsys: fsys, path: path})
+		}
+	}
+}
+
+// Tables which are not truncated by Open, like migrations table
+func WithKeep(tables ...string) Option {
+	return func(c *config) {
+		c.keep = append(c.keep, tables...)
+	}
+}
+
+// Disables truncation of tables by Open
+func WithoutTruncate() Option {
+	return func(c *config) {
+		c.noClean = true
+	}
+}
+
+// Truncates all tables of current schema except kept ones and restarts their sequences
+func Truncate(t testing.TB, db *sql.DB, keep ...string) {
+	t.Helper()
+	rows, err := qb.New(db).SQL("SELECT tablename FROM pg_tables WHERE schemaname = current_schema()").Rows()
+	if err != nil {
+		t.Fatalf("testdb: list tables: %v", err)
+	}
+	tables := make([]string, 0, len(rows))
+	for _, row := range rows {
+		name, _ := row["tablename"].(string)
+		if name != "" && !slices.Contains(keep, name) {
+			tables = append(tables, quote(name))
+		}
+	}
+	if len(tables) == 0 {
+		return
+	}
+	if err := qb.New(db).SQL("TRUNCATE " + strings.Join(tables, ", ") + " RESTART IDENTITY CASCADE").Exec(); err != nil {
+		t.Fatalf("testdb: truncate: %v", err)
+	}
+}
+
+// Inserts fixture rows into table
+//
+// Ex.: testdb.Load(t, db, "users", []jvm.M{{"name": "jv", "email": "jv@example.com"}})
+func Load(t testing.TB, db *sql.DB, table string, rows []jvm.M) {
+	t.Helper()
+	for i, row := range rows {
+		if err := qb.New(db).Insert(table).ColsWithParams(row).Exec(); err != nil {
+			t.Fatalf("testdb: load %s row %d: %v", table, i, err)
+		}
+	}
+}
+
+func applySchema(t testing.TB, db *sql.DB, dsn string, f schemaFile) {
+	t.Helper()
+	appliedMu.Lock()
+	defer appliedMu.Unlock()
+	key := dsn + "\x00" + f.path
+	if applied[key] {
+		return
+	}
+	var b []byte
+	var err error
+	if f.fsys != nil {
+		b, err = fs.ReadFile(f.fsys, f.path)
+	} else {
+		b, err = os.ReadFile(f.path)
+	}
+	if err != nil {
+		t.Fatalf("testdb: read schema: %v", err)
+	}
+	if err := qb.New(db).SQL(string(b)).Exec(); err != nil {
+		t.Fatalf("testdb: apply schema %s: %v", f.path, err)
+	}
+	applied[key] = true
+}
+
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,21 @@
+package ticker
+
+import "time"
+
+// Source of time for tickers. Allows to control time in tests
+type Clock interface {
+	// Current time
+	Now() time.Time
+	// Schedules call of f after duration d
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
@@ -0,0 +1,364 @@
+package ticker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+)
+
+var cronShortcuts = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonths = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronWeekdays = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField  = cronField{min: 0, max: 59}
+	hourField    = cronField{min: 0, max: 23}
+	domField     = cronField{min: 1, max: 31}
+	monthField   = cronField{min: 1, max: 12, names: cronMonths}
+	weekdayField = cronField{min: 0, max: 7, names: cronWeekdays}
+)
+
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+	loc                           *time.Location
+}
+
+type cron[T comparable] struct {
+	config
+	mu       sync.Mutex
+	id       T
+	schedule *cronSchedule
+	onFire   FinishFunc[T]
+	timer    Timer
+	unwatch  func() bool
+	nextAt   time.Time
+	fireAt   time.Time
+	gen      uint64
+	started  bool
+	stopped  bool
+}
+
+// Cron ticker constructor.
+// Spec is standard 5-field cron expression (minute, hour, day of month, month, day of week)
+// or one of shortcuts @yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly.
+// Schedule is evaluated in loc, local time is used if loc is nil.
+// Wall clock times skipped by DST transition are not fired, repeated ones are fired once.
+// Reset overrides the next fire time only, after that schedule continues.
+//
+// Ex.: ticker.NewCron("cleanup", "30 3 * * mon-fri", time.UTC, cleanup)
+func NewCron[T comparable](id T, spec string, loc *time.Location, onFire FinishFunc[T], opts ...Option) (Ticker[T], error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	schedule, err := parseCron(spec, loc)
+	if err != nil {
+		return nil, err
+	}
+	return &cron[T]{
+		config:   newConfig[T](opts),
+		id:       id,
+		schedule: schedule,
+		onFire:   onFire,
+	}, nil
+}
+
+func (c *cron[T]) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.started || c.stopped {
+		return
+	}
+	c.started = true
+	c.trackActive(1)
+	c.watch()
+	c.scheduleAt(c.schedule.next(c.now()))
+}
+
+func (c *cron[T]) Reset(finishAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return
+	}
+	if !c.started {
+		c.started = true
+		c.trackActive(1)
+		c.watch()
+	}
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.scheduleAt(finishAt)
+}
+
+// Moves the next fire time only, after that schedule continues
+func (c *cron[T]) Extend(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped || c.nextAt.IsZero() {
+		return c.fireAt
+	}
+	c.timer.Stop()
+	c.scheduleAt(c.nextAt.Add(d))
+	return c.fireAt
+}
+
+func (c *cron[T]) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.started && !c.stopped {
+		c.trackActive(-1)
+	}
+	c.stopped = true
+	if c.unwatch != nil {
+		c.unwatch()
+		c.unwatch = nil
+	}
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+}
+
+// Time left to the next fire. Zero if cron is stopped or schedule has no more fire times
+func (c *cron[T]) Remaining() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped || c.fireAt.IsZero() {
+		return 0
+	}
+	return max(c.until(c.fireAt), 0)
+}
+
+// Time of the next fire. Zero if cron is not started or schedule has no more fire times
+func (c *cron[T]) FireAt() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.fireAt
+}
+
+// Cron is pending until it is stopped
+func (c *cron[T]) State() State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stopped {
+		return StateStopped
+	}
+	return StatePending
+}
+
+// Stops cron when context of WithContext is done. Must be called with locked mutex
+func (c *cron[T]) watch() {
+	if c.ctx != nil {
+		c.unwatch = context.AfterFunc(c.ctx, c.Stop)
+	}
+}
+
+func (c *cron[T]) scheduleAt(at time.Time) {
+	c.gen++
+	c.nextAt = at
+	c.fireAt = time.Time{}
+	if at.IsZero() {
+		return
+	}
+	c.fireAt = at.Add(c.delay())
+	c.wait(c.gen, at)
+}
+
+// Must be called with locked mutex
+func (c *cron[T]) wait(gen uint64, at time.Time) {
+	c.timer = c.armAt(c.fireAt, func() {
+		c.fire(gen, at)
+	}, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if gen == c.gen && !c.stopped {
+			c.wait(gen, at)
+		}
+	})
+}
+
+func (c *cron[T]) fire(gen uint64, at time.Time) {
+	c.mu.Lock()
+	if c.stopped || gen != c.gen {
+		c.mu.Unlock()
+		return
+	}
+	now := c.now()
+	delay := now.Sub(c.fireAt)
+	if now.Before(at) {
+		now = at
+	}
+	c.scheduleAt(c.schedule.next(now))
+	c.mu.Unlock()
+	start := time.Now()
+	run(&c.config, c.id, func() error {
+		c.onFire(c.id)
+		return nil
+	})
+	c.trackFired(delay, time.Since(start))
+}
+
+func parseCron(spec string, loc *time.Location) (*cronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@") {
+		expanded, ok := cronShortcuts[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown cron shortcut %q", jve.ErrBadFormat, spec)
+		}
+		spec = expanded
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: cron spec %q must have 5 fields", jve.ErrBadFormat, spec)
+	}
+
+	s := &cronSchedule{loc: loc}
+	var err error
+	if s.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if s.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if s.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if s.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.dow, err = weekdayField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	// 7 is an alias for Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// Parses field of cron spec into bit set
+func (f cronField) parse(field string) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepStr)
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("%w: bad cron step %q", jve.ErrBadFormat, item)
+			}
+		}
+
+		var from, to int
+		if rng == "*" {
+			from, to = f.min, f.max
+		} else {
+			lo, hi, isRange := strings.Cut(rng, "-")
+			var err error
+			if from, err = f.value(lo); err != nil {
+				return 0, err
+			}
+			to = from
+			if isRange {
+				if to, err = f.value(hi); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				to = f.max
+			}
+		}
+		if from > to {
+			return 0, fmt.Errorf("%w: bad cron range %q", jve.ErrBadFormat, item)
+		}
+
+		for i := from; i <= to; i += step {
+			bits |= 1 << i
+		}
+	}
+	return bits, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%w: bad cron value %q", jve.ErrBadFormat, s)
+	}
+	return v, nil
+}
+
+// Returns the first fire time after the given time or zero time if there is none in next 5 years
+func (s *cronSchedule) next(after time.Time) time.Time {
+	t := after.In(s.loc)
+	hour, minute := t.Hour(), t.Minute()+1
+	// days are iterated in UTC to avoid DST issues with date arithmetic
+	date := time.Date(t.Year(), t.Month(), t.Day(), 12, 0, 0, 0, time.UTC)
+	limit := date.AddDate(5, 0, 0)
+	for ; date.Before(limit); date, hour, minute = date.AddDate(0, 0, 1), 0, 0 {
+		if !s.matchDay(date) {
+			continue
+		}
+		for ; hour < 24; hour, minute = hour+1, 0 {
+			if s.hour&(1<<hour) == 0 {
+				continue
+			}
+			for ; minute < 60; minute++ {
+				if s.minute&(1<<minute) == 0 {
+					continue
+				}
+				c := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, s.loc)
+				// time does not exist in this day because of DST transition
+				if c.Hour() != hour || c.Minute() != minute {
+					continue
+				}
+				if c.After(after) {
+					return c
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func (s *cronSchedule) matchDay(date time.Time) bool {
+	if s.month&(1<<uint(date.Month())) == 0 {
+		return false
+	}
+	dom := s.dom&(1<<date.Day()) != 0
+	dow := s.dow&(1<<uint(date.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,118 @@
+package ticker
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+)
+
+func TestParseCronRejectsBadSpecs(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"* * * foo *",
+		"@every",
+	} {
+		if _, err := parseCron(spec, time.UTC); !errors.Is(err, jve.ErrBadFormat) {
+			t.Errorf("parseCron(%q) error = %v, want ErrBadFormat", spec, err)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// Friday
+	after := time.Date(2024, 5, 10, 10, 7, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, 5, 10, 10, 15, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2024, 5, 13, 9, 0, 0, 0, time.UTC)},
+		{"0 12 * * *", time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 5, 10, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jun *", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		// 7 is Sunday
+		{"0 0 * * 7", time.Date(2024, 5, 12, 0, 0, 0, 0, time.UTC)},
+		// day of month or day of week when both are restricted
+		{"0 0 20 * sat", time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	} {
+		s, err := parseCron(tt.spec, time.UTC)
+		if err != nil {
+			t.Fatalf("parseCron(%q): %v", tt.spec, err)
+		}
+		if got := s.next(after); !got.Equal(tt.want) {
+			t.Errorf("next of %q = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestCronNextAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name  string
+		spec  string
+		after time.Time
+		want  []time.Time
+	}{
+		{
+			// 2:30 doesn't exist on 2024-03-10, clocks jump from 2:00 to 3:00
+			name:  "skipped time is not fired",
+			spec:  "30 2 * * *",
+			after: time.Date(2024, 3, 9, 3, 0, 0, 0, loc),
+			want: []time.Time{
+				time.Date(2024, 3, 11, 2, 30, 0, 0, loc),
+			},
+		},
+		{
+			name:  "hour after skipped time",
+			spec:  "0 3 * * *",
+			after: time.Date(2024, 3, 10, 0, 0, 0, 0, loc),
+			want: []time.Time{
+				time.Date(2024, 3, 10, 3, 0, 0, 0, loc),
+				time.Date(2024, 3, 11, 3, 0, 0, 0, loc),
+			},
+		},
+		{
+			// 1:30 happens twice on 2024-11-03, clocks go back from 2:00 to 1:00
+			name:  "repeated time is fired once",
+			spec:  "30 1 * * *",
+			after: time.Date(2024, 11, 3, 0, 0, 0, 0, loc),
+			want: []time.Time{
+				time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC),
+				time.Date(2024, 11, 4, 6, 30, 0, 0, time.UTC),
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCron(tt.spec, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			at := tt.after
+			for _, want := range tt.want {
+				at = s.next(at)
+				if !at.Equal(want) {
+					t.Fatalf("next = %v, want %v", at, want.In(loc))
+				}
+			}
+		})
+	}
+}
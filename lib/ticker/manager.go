@@ -0,0 +1,271 @@
+package ticker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+)
+
+type manager[T comparable] struct {
+	mu        sync.Mutex
+	tickers   map[T]Ticker[T]
+	store     Store[T]
+	onRestore FinishFunc[T]
+	opts      []Option
+	cfg       config
+	metrics   *Metrics
+	window    time.Duration
+	onBatch   func(ids []T)
+	batchMu   sync.Mutex
+	batch     []T
+}
+
+// Option of manager
+type ManagerOption[T comparable] func(*manager[T])
+
+// Registry of tickers keyed by ID. Safe for concurrent use.
+// Ticker is removed from registry when it finishes or is canceled
+type Manager[T comparable] interface {
+	// Creates and starts ticker. Ticker with the same ID is stopped and replaced
+	//
+	// Ex.: m.Add(sessionID, time.Now().Add(time.Hour), onExpire, ticker.WithJitter(time.Second))
+	Add(id T, finishAt time.Time, onFinish FinishFunc[T], opts ...Option) error
+	// Reset ticker for new time to finish. Returns ErrNotFound for unknown ID
+	Reset(id T, finishAt time.Time) error
+	// Stops and removes ticker. Returns ErrNotFound for unknown ID
+	Cancel(id T) error
+	// Loads pending tickers from the store and starts them with restore callback.
+	// Overdue tickers are fired immediately
+	Restore(ctx context.Context) error
+	// Count of active tickers
+	Len() int
+	// IDs of active tickers
+	IDs() []T
+	// Stops and removes all tickers. Persisted tickers are kept in the store
+	StopAll()
+	// Metrics of tickers created by manager
+	Stats() Stats
+}
+
+// Manager constructor
+//
+// Ex.: ticker.NewManager(ticker.WithStore(store, onExpire))
+func NewManager[T comparable](opts ...ManagerOption[T]) Manager[T] {
+	m := &manager[T]{
+		tickers: make(map[T]Ticker[T]),
+		metrics: NewMetrics(),
+	}
+	m.opts = append(m.opts, WithMetrics(m.metrics))
+	for _, opt := range opts {
+		opt(m)
+	}
+	m.cfg = newConfig[T](m.opts)
+	return m
+}
+
+// Persists tickers of manager in the store, so they survive process restarts.
+// Callback can't be persisted, so onRestore is used for tickers loaded by Restore
+func WithStore[T comparable](store Store[T], onRestore FinishFunc[T]) ManagerOption[T] {
+	return func(m *manager[T]) {
+		m.store = store
+		m.onRestore = onRestore
+	}
+}
+
+// Default options of tickers created by manager
+//
+// Ex.: ticker.NewManager(ticker.WithTickerOptions[string](ticker.WithScheduler(s)))
+func WithTickerOptions[T comparable](opts ...Option) ManagerOption[T] {
+	return func(m *manager[T]) {
+		m.opts = append(m.opts, opts...)
+	}
+}
+
+// Coalesces tickers fired within the window into one call of onBatch.
+// Window starts with the first fired ticker, so callbacks are delayed up to window.
+// Callbacks passed to Add and restore callback are not called
+//
+// Ex.: ticker.NewManager(ticker.WithCoalesce(100*time.Millisecond, closeAuctions))
+func WithCoalesce[T comparable](window time.Duration, onBatch func(ids []T)) ManagerOption[T] {
+	return func(m *manager[T]) {
+		m.window = window
+		m.onBatch = onBatch
+	}
+}
+
+func (m *manager[T]) Add(id T, finishAt time.Time, onFinish FinishFunc[T], opts ...Option) error {
+	if m.store != nil {
+		if err := m.store.Save(context.Background(), id, finishAt); err != nil {
+			return err
+		}
+	}
+	m.mu.Lock()
+	if old, ok := m.tickers[id]; ok {
+		old.Stop()
+	}
+	t := m.newTicker(id, finishAt, onFinish, opts...)
+	m.mu.Unlock()
+	t.Start()
+	return nil
+}
+
+func (m *manager[T]) Reset(id T, finishAt time.Time) error {
+	m.mu.Lock()
+	t, ok := m.tickers[id]
+	m.mu.Unlock()
+	if !ok {
+		return jve.ErrNotFound
+	}
+	if m.store != nil {
+		if err := m.store.Save(context.Background(), id, finishAt); err != nil {
+			return err
+		}
+	}
+	t.Reset(finishAt)
+	return nil
+}
+
+func (m *manager[T]) Cancel(id T) error {
+	m.mu.Lock()
+	t, ok := m.tickers[id]
+	delete(m.tickers, id)
+	m.mu.Unlock()
+	if !ok {
+		return jve.ErrNotFound
+	}
+	t.Stop()
+	if m.store != nil {
+		return m.store.Delete(context.Background(), id)
+	}
+	return nil
+}
+
+func (m *manager[T]) Restore(ctx context.Context) error {
+	if m.store == nil {
+		return nil
+	}
+	pending, err := m.store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	started := make([]Ticker[T], 0, len(pending))
+	m.mu.Lock()
+	for id, finishAt := range pending {
+		if _, ok := m.tickers[id]; ok {
+			continue
+		}
+		started = append(started, m.newTicker(id, finishAt, m.onRestore))
+	}
+	m.mu.Unlock()
+	for _, t := range started {
+		t.Start()
+	}
+	return nil
+}
+
+func (m *manager[T]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.tickers)
+}
+
+func (m *manager[T]) IDs() []T {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]T, 0, len(m.tickers))
+	for id := range m.tickers {
+		result = append(result, id)
+	}
+	return result
+}
+
+func (m *manager[T]) StopAll() {
+	m.mu.Lock()
+	tickers := m.tickers
+	m.tickers = make(map[T]Ticker[T])
+	m.mu.Unlock()
+	for _, t := range tickers {
+		t.Stop()
+	}
+}
+
+func (m *manager[T]) Stats() Stats {
+	return m.metrics.Stats()
+}
+
+// Creates and registers ticker. Must be called with locked mutex
+func (m *manager[T]) newTicker(id T, finishAt time.Time, onFinish FinishFunc[T], opts ...Option) Ticker[T] {
+	var t Ticker[T]
+	t = New(id, finishAt, func(id T) {
+		m.remove(id, t)
+		if m.onBatch != nil {
+			m.collect(id)
+			return
+		}
+		defer m.forget(id)
+		onFinish(id)
+	}, slices.Concat(m.opts, opts)...)
+	m.tickers[id] = t
+	return t
+}
+
+// Removes ticker if it is still registered for the ID
+func (m *manager[T]) remove(id T, t Ticker[T]) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tickers[id] == t {
+		delete(m.tickers, id)
+	}
+}
+
+// Adds fired ticker to the batch. The first ticker of batch schedules its flush
+func (m *manager[T]) collect(id T) {
+	m.batchMu.Lock()
+	defer m.batchMu.Unlock()
+	m.batch = append(m.batch, id)
+	if len(m.batch) == 1 {
+		m.cfg.afterFunc(m.window, m.flush)
+	}
+}
+
+func (m *manager[T]) flush() {
+	m.batchMu.Lock()
+	ids := m.batch
+	m.batch = nil
+	m.batchMu.Unlock()
+
+	defer func() {
+		r := recover()
+		for _, id := range ids {
+			if r != nil && m.cfg.recover {
+				report(&m.cfg, id, r)
+			}
+			m.forget(id)
+		}
+		if r != nil && !m.cfg.recover {
+			panic(r)
+		}
+	}()
+	m.onBatch(ids)
+}
+
+// Deletes fired ticker from the store unless it was added again by callback.
+// Error of the store is routed to error hook, otherwise ticker would fire again after Restore
+func (m *manager[T]) forget(id T) {
+	if m.store == nil {
+		return
+	}
+	m.mu.Lock()
+	_, active := m.tickers[id]
+	m.mu.Unlock()
+	if active {
+		return
+	}
+	if err := m.store.Delete(context.Background(), id); err != nil {
+		report(&m.cfg, id, fmt.Errorf("delete fired ticker from store: %w", err))
+	}
+}
@@ -0,0 +1,102 @@
+package ticker
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// Counters of tickers. Safe for concurrent use and may be shared by many tickers.
+// Implements expvar.Var, so it can be published directly
+//
+// Ex.: metrics := ticker.NewMetrics(); expvar.Publish("tickers", metrics)
+type Metrics struct {
+	active        atomic.Int64
+	fired         atomic.Uint64
+	overdue       atomic.Uint64
+	callbackTotal atomic.Int64
+	callbackMax   atomic.Int64
+	delayTotal    atomic.Int64
+	delayMax      atomic.Int64
+}
+
+// Snapshot of metrics
+type Stats struct {
+	// Count of pending tickers
+	Active int64 `json:"active"`
+	// Count of fired tickers
+	Fired uint64 `json:"fired"`
+	// Count of tickers already overdue when started
+	OverdueOnStart uint64 `json:"overdue_on_start"`
+	// Total and maximum duration of finish callbacks
+	CallbackTotal time.Duration `json:"callback_total"`
+	CallbackMax   time.Duration `json:"callback_max"`
+	// Total and maximum delay between fire time and actual fire
+	DelayTotal time.Duration `json:"delay_total"`
+	DelayMax   time.Duration `json:"delay_max"`
+}
+
+// Metrics constructor
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// Collects metrics of ticker. Several metrics may be used for the same ticker
+func WithMetrics(m *Metrics) Option {
+	return func(c *config) {
+		c.metrics = append(c.metrics, m)
+	}
+}
+
+// Returns snapshot of metrics
+func (m *Metrics) Stats() Stats {
+	return Stats{
+		Active:         m.active.Load(),
+		Fired:          m.fired.Load(),
+		OverdueOnStart: m.overdue.Load(),
+		CallbackTotal:  time.Duration(m.callbackTotal.Load()),
+		CallbackMax:    time.Duration(m.callbackMax.Load()),
+		DelayTotal:     time.Duration(m.delayTotal.Load()),
+		DelayMax:       time.Duration(m.delayMax.Load()),
+	}
+}
+
+// Returns snapshot of metrics as JSON
+func (m *Metrics) String() string {
+	b, err := json.Marshal(m.Stats())
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
+
+func (c *config) trackActive(delta int64) {
+	for _, m := range c.metrics {
+		m.active.Add(delta)
+	}
+}
+
+func (c *config) trackOverdue() {
+	for _, m := range c.metrics {
+		m.overdue.Add(1)
+	}
+}
+
+func (c *config) trackFired(delay time.Duration, callback time.Duration) {
+	for _, m := range c.metrics {
+		m.fired.Add(1)
+		m.delayTotal.Add(int64(delay))
+		storeMax(&m.delayMax, int64(delay))
+		m.callbackTotal.Add(int64(callback))
+		storeMax(&m.callbackMax, int64(callback))
+	}
+}
+
+func storeMax(v *atomic.Int64, value int64) {
+	for {
+		current := v.Load()
+		if value <= current || v.CompareAndSwap(current, value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package ticker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"time"
+)
+
+type config struct {
+	jitter     time.Duration
+	onError    any
+	clock      Clock
+	ctx        context.Context
+	driftCheck time.Duration
+	metrics    []*Metrics
+	timeout    time.Duration
+	recover    bool
+}
+
+// Option of ticker
+type Option func(*config)
+
+// Delays every fire time by random duration in range [0, max),
+// so tickers created for the same time don't fire at the same instant
+func WithJitter(max time.Duration) Option {
+	return func(c *config) {
+		c.jitter = max
+	}
+}
+
+// Routes panics and errors of finish callback to the hook instead of the standard logger.
+// Type of ID must match the type of ticker ID, otherwise constructor of ticker panics
+func OnError[T comparable](onError ErrorFunc[T]) Option {
+	return func(c *config) {
+		c.onError = onError
+	}
+}
+
+// Recovers panics of finish callback and routes them to the OnError hook. Enabled by default,
+// with WithRecover(false) panic of callback crashes the process
+func WithRecover(enabled bool) Option {
+	return func(c *config) {
+		c.recover = enabled
+	}
+}
+
+// Schedules ticker on the shared scheduler instead of a runtime timer
+func WithScheduler(s *Scheduler) Option {
+	return WithClock(s)
+}
+
+// Uses clock as source of time and timers
+func WithClock(clock Clock) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
+// Binds ticker to context. When context is done the ticker is stopped
+// without calling finish callback. Replaces context of NewWithContext
+func WithContext(ctx context.Context) Option {
+	return func(c *config) {
+		c.ctx = ctx
+	}
+}
+
+// Rechecks fire time against wall clock at least once per interval and fires immediately
+// if it is overdue. Keeps tickers anchored to absolute time correct after host suspend
+// or wall clock adjustments, which are not tracked by the monotonic clock of timers
+func WithDriftCheck(interval time.Duration) Option {
+	return func(c *config) {
+		c.driftCheck = interval
+	}
+}
+
+// Sets deadline for context passed to finish callback of NewWithContext.
+// Deadline only cancels the context and doesn't bound runtime of callback, which must watch
+// the context. Callback returned after the deadline is reported to the OnError hook with
+// context.DeadlineExceeded. Ignored by New and NewWithError, their callbacks don't receive context
+func WithTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.timeout = d
+	}
+}
+
+// Applies options of ticker with ID of type T. Panics if OnError hook is set for another type of ID
+func newConfig[T comparable](opts []Option) config {
+	c := config{
+		clock:   realClock{},
+		recover: true,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.onError != nil {
+		if _, ok := c.onError.(ErrorFunc[T]); !ok {
+			panic(fmt.Sprintf("ticker: OnError hook %T doesn't match ID type %T", c.onError, *new(T)))
+		}
+	}
+	return c
+}
+
+func (c *config) now() time.Time {
+	return c.clock.Now()
+}
+
+func (c *config) until(t time.Time) time.Duration {
+	return t.Sub(c.clock.Now())
+}
+
+// Returns random delay for fire time
+func (c *config) delay() time.Duration {
+	if c.jitter <= 0 {
+		return 0
+	}
+	return rand.N(c.jitter)
+}
+
+// Schedules call of f after duration d
+func (c *config) afterFunc(d time.Duration, f func()) Timer {
+	return c.clock.AfterFunc(d, f)
+}
+
+// Schedules fire at the time. With drift check long waits are split
+// and recheck is called instead of fire to schedule the rest of the wait
+func (c *config) armAt(at time.Time, fire func(), recheck func()) Timer {
+	d := c.until(at)
+	if c.driftCheck > 0 && d > c.driftCheck {
+		return c.afterFunc(c.driftCheck, recheck)
+	}
+	return c.afterFunc(d, fire)
+}
+
+// Calls finish callback recovering panics unless it is disabled. Panics and errors are routed to error hook
+func run[T comparable](c *config, id T, fn func() error) {
+	if c.recover {
+		defer func() {
+			if r := recover(); r != nil {
+				report(c, id, r)
+			}
+		}()
+	}
+	if err := fn(); err != nil {
+		report(c, id, err)
+	}
+}
+
+func report[T comparable](c *config, id T, recovered any) {
+	if onError, ok := c.onError.(ErrorFunc[T]); ok {
+		onError(id, recovered)
+		return
+	}
+	log.Printf("ticker %v: %v", id, recovered)
+}
@@ -0,0 +1,170 @@
+package ticker
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// Scheduled call which can be stopped
+type Timer interface {
+	// Prevents call. Returns false if call is already done or stopped
+	Stop() bool
+}
+
+// Scheduler multiplexes timers of many tickers onto one dispatcher goroutine
+// and a fixed pool of workers calling the callbacks, so the count of goroutines
+// does not depend on the count of tickers. Long callbacks hold a worker,
+// so slow work should be passed to another goroutine.
+//
+// Ex.: s := ticker.NewScheduler(4); defer s.Close(); ticker.New(id, finishAt, onFinish, ticker.WithScheduler(s))
+type Scheduler struct {
+	mu      sync.Mutex
+	entries entryHeap
+	wake    chan struct{}
+	tasks   chan func()
+	done    chan struct{}
+	once    sync.Once
+}
+
+type entry struct {
+	s     *Scheduler
+	at    time.Time
+	f     func()
+	index int
+}
+
+type entryHeap []*entry
+
+// Scheduler constructor. Starts dispatcher and workers
+func NewScheduler(workers int) *Scheduler {
+	if workers < 1 {
+		workers = 1
+	}
+	s := &Scheduler{
+		entries: make(entryHeap, 0),
+		wake:    make(chan struct{}, 1),
+		tasks:   make(chan func()),
+		done:    make(chan struct{}),
+	}
+	for i := 0; i < workers; i++ {
+		go s.work()
+	}
+	go s.dispatch()
+	return s
+}
+
+// Current time. Scheduler implements Clock
+func (s *Scheduler) Now() time.Time {
+	return time.Now()
+}
+
+// Schedules call of f after duration d
+func (s *Scheduler) AfterFunc(d time.Duration, f func()) Timer {
+	e := &entry{
+		s:  s,
+		at: time.Now().Add(d),
+		f:  f,
+	}
+	s.mu.Lock()
+	heap.Push(&s.entries, e)
+	first := e.index == 0
+	s.mu.Unlock()
+	if first {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+	return e
+}
+
+// Count of scheduled calls
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// Stops dispatcher and workers. Scheduled calls are dropped
+func (s *Scheduler) Close() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+func (s *Scheduler) dispatch() {
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	for {
+		s.mu.Lock()
+		for len(s.entries) > 0 && !s.entries[0].at.After(time.Now()) {
+			e := heap.Pop(&s.entries).(*entry)
+			s.mu.Unlock()
+			select {
+			case s.tasks <- e.f:
+			case <-s.done:
+				return
+			}
+			s.mu.Lock()
+		}
+		var wait <-chan time.Time
+		if len(s.entries) > 0 {
+			timer.Reset(time.Until(s.entries[0].at))
+			wait = timer.C
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-s.wake:
+			timer.Stop()
+		case <-s.done:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+func (s *Scheduler) work() {
+	for {
+		select {
+		case f := <-s.tasks:
+			f()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (e *entry) Stop() bool {
+	e.s.mu.Lock()
+	defer e.s.mu.Unlock()
+	if e.index < 0 {
+		return false
+	}
+	heap.Remove(&e.s.entries, e.index)
+	return true
+}
+
+func (h entryHeap) Len() int           { return len(h) }
+func (h entryHeap) Less(i, j int) bool { return h[i].at.Before(h[j].at) }
+func (h entryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *entryHeap) Push(x any) {
+	e := x.(*entry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+func (h *entryHeap) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*h = old[:n-1]
+	return e
+}
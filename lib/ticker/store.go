@@ -0,0 +1,76 @@
+package ticker
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+	"github.com/jvnonce/jv-go-utils/lib/qb"
+)
+
+// Persistent storage of pending tickers
+type Store[T comparable] interface {
+	// Saves or updates time to finish of the ticker
+	Save(ctx context.Context, id T, finishAt time.Time) error
+	// Loads all pending tickers
+	Load(ctx context.Context) (map[T]time.Time, error)
+	// Deletes ticker
+	Delete(ctx context.Context, id T) error
+}
+
+type pgStore[T comparable] struct {
+	db    *sql.DB
+	table string
+}
+
+// PostgreSQL store constructor. Table must have id and finish_at columns.
+// Type of id column must be scanned by the driver into T
+//
+// Ex.: CREATE TABLE timers (id text PRIMARY KEY, finish_at timestamptz NOT NULL)
+func NewPGStore[T comparable](db *sql.DB, table string) Store[T] {
+	return &pgStore[T]{
+		db:    db,
+		table: table,
+	}
+}
+
+func (s *pgStore[T]) Save(ctx context.Context, id T, finishAt time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return qb.New(s.db).SQL(
+		"INSERT INTO "+s.table+" (id, finish_at) VALUES (?, ?)\nON CONFLICT (id) DO UPDATE SET finish_at = EXCLUDED.finish_at",
+		id, finishAt,
+	).Exec()
+}
+
+func (s *pgStore[T]) Load(ctx context.Context) (map[T]time.Time, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	rows, err := qb.New(s.db).Select(s.table).Columns("id", "finish_at").Rows()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[T]time.Time, len(rows))
+	for _, row := range rows {
+		id, ok := row["id"].(T)
+		if !ok {
+			return nil, jve.ErrBadType
+		}
+		finishAt, ok := row["finish_at"].(time.Time)
+		if !ok {
+			return nil, jve.ErrBadType
+		}
+		result[id] = finishAt
+	}
+	return result, nil
+}
+
+func (s *pgStore[T]) Delete(ctx context.Context, id T) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return qb.New(s.db).Delete(s.table).Where("id=?", id).Exec()
+}
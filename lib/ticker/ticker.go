@@ -1,27 +1,129 @@
 package ticker
 
-import "time"
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
 
 type FinishFunc[T comparable] func(id T)
 
+// Finish callback receiving context of the ticker
+type FinishCtxFunc[T comparable] func(ctx context.Context, id T)
+
+// Finish callback returning error
+type FinishErrFunc[T comparable] func(id T) error
+
+// Hook for panics and errors of finish callback.
+// Recovered is either error returned by callback or value passed to panic
+type ErrorFunc[T comparable] func(id T, recovered any)
+
+// State of ticker
+type State int
+
+const (
+	// Ticker waits for time to finish
+	StatePending State = iota
+	// Finish callback is called
+	StateFired
+	// Ticker is stopped or its context is done
+	StateStopped
+)
+
+func (s State) String() string {
+	switch s {
+	case StatePending:
+		return "pending"
+	case StateFired:
+		return "fired"
+	case StateStopped:
+		return "stopped"
+	}
+	return "unknown"
+}
+
 type ticker[T comparable] struct {
+	config
+	mu       sync.Mutex
+	parent   context.Context
+	ctx      context.Context
+	cancel   context.CancelFunc
+	unwatch  func() bool
 	id       T
 	finishAt time.Time
-	onFinish FinishFunc[T]
-	ticker   *time.Ticker
+	fireAt   time.Time
+	state    State
+	started  bool
+	active   bool
+	gen      uint64
+	timer    Timer
+	onFinish func(context.Context, T) error
 }
 
 // Ticker interface
 type Ticker[T comparable] interface {
 	// Starts ticker
 	Start()
-	// Reset ticker for new time to finish
+	// Reset ticker for new time to finish. Fired ticker is armed again
 	Reset(finishAt time.Time)
+	// Stops ticker without calling finish callback
+	Stop()
+	// Atomically moves time to finish by duration and returns new fire time.
+	// Fired ticker is armed again for the moved time
+	//
+	// Ex.: t.Extend(5 * time.Minute)
+	Extend(d time.Duration) time.Time
+	// Time left to fire. Zero if ticker is not pending
+	Remaining() time.Duration
+	// Time of the next fire including jitter
+	FireAt() time.Time
+	// State of ticker
+	State() State
 }
 
 // Ticker constructor
-func New[T comparable](id T, finishAt time.Time, onFinish FinishFunc[T]) Ticker[T] {
+//
+// Ex.: ticker.New(id, finishAt, onFinish, ticker.WithContext(ctx), ticker.WithJitter(time.Second))
+func New[T comparable](id T, finishAt time.Time, onFinish FinishFunc[T], opts ...Option) Ticker[T] {
+	t := newTicker(context.Background(), id, finishAt, func(_ context.Context, id T) error {
+		onFinish(id)
+		return nil
+	}, opts)
+	t.ignoreTimeout()
+	return t
+}
+
+// Ticker constructor bound to context.
+// When context is done the ticker is stopped without calling onFinish.
+// Callback receives context of the ticker, limited by WithTimeout if it is set
+//
+// Ex.: ticker.NewWithContext(ctx, id, finishAt, onFinish, ticker.WithTimeout(10*time.Second))
+func NewWithContext[T comparable](ctx context.Context, id T, finishAt time.Time, onFinish FinishCtxFunc[T], opts ...Option) Ticker[T] {
+	return newTicker(ctx, id, finishAt, func(ctx context.Context, id T) error {
+		onFinish(ctx, id)
+		return nil
+	}, opts)
+}
+
+// Ticker constructor with callback returning error.
+// Error is routed to the OnError hook
+func NewWithError[T comparable](id T, finishAt time.Time, onFinish FinishErrFunc[T], opts ...Option) Ticker[T] {
+	t := newTicker(context.Background(), id, finishAt, func(_ context.Context, id T) error {
+		return onFinish(id)
+	}, opts)
+	t.ignoreTimeout()
+	return t
+}
+
+func newTicker[T comparable](ctx context.Context, id T, finishAt time.Time, onFinish func(context.Context, T) error, opts []Option) *ticker[T] {
+	cfg := newConfig[T](opts)
+	if cfg.ctx != nil {
+		ctx = cfg.ctx
+	}
 	return &ticker[T]{
+		config:   cfg,
+		parent:   ctx,
 		id:       id,
 		finishAt: finishAt,
 		onFinish: onFinish,
@@ -29,36 +131,200 @@ func New[T comparable](id T, finishAt time.Time, onFinish FinishFunc[T]) Ticker[
 }
 
 func (t *ticker[T]) Start() {
-	go func() {
-		now := time.Now()
-		if t.finishAt.Before(now) {
-			go t.onFinish(t.id)
-			return
-		}
-		d := t.finishAt.Sub(now)
-		t.ticker = time.NewTicker(d)
-		defer func() {
-			t.ticker.Stop()
-		}()
-		for {
-			<-t.ticker.C
-			t.onFinish(t.id)
-			return
-		}
-	}()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.started || t.state == StateStopped {
+		return
+	}
+	t.start()
 }
 
 func (t *ticker[T]) Reset(finishAt time.Time) {
-	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state == StateStopped || t.parent.Err() != nil {
+		return
+	}
 	t.finishAt = finishAt
-	if t.finishAt.Before(now) {
-		go t.onFinish(t.id)
+	if !t.started {
+		t.start()
+		return
+	}
+	t.timer.Stop()
+	t.arm()
+}
+
+func (t *ticker[T]) Extend(d time.Duration) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state == StateStopped || t.parent.Err() != nil {
+		return t.fireAtLocked()
+	}
+	t.finishAt = t.finishAt.Add(d)
+	if t.started {
+		t.timer.Stop()
+		t.arm()
+	}
+	return t.fireAtLocked()
+}
+
+func (t *ticker[T]) Stop() {
+	t.mu.Lock()
+	if t.state == StatePending {
+		t.state = StateStopped
+	}
+	t.deactivate()
+	t.release()
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	t.gen++
+	t.mu.Unlock()
+}
+
+func (t *ticker[T]) Remaining() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.state != StatePending {
+		return 0
+	}
+	return max(t.until(t.fireAtLocked()), 0)
+}
+
+func (t *ticker[T]) FireAt() time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.fireAtLocked()
+}
+
+func (t *ticker[T]) State() State {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state
+}
+
+// Must be called with locked mutex
+func (t *ticker[T]) fireAtLocked() time.Time {
+	if !t.started {
+		return t.finishAt
+	}
+	return t.fireAt
+}
+
+// Must be called with locked mutex
+func (t *ticker[T]) start() {
+	t.started = true
+	if t.finishAt.Before(t.now()) {
+		t.trackOverdue()
+	}
+	t.arm()
+}
+
+// Schedules fire. Must be called with locked mutex
+func (t *ticker[T]) arm() {
+	t.gen++
+	gen := t.gen
+	t.state = StatePending
+	t.bind()
+	if !t.active {
+		t.active = true
+		t.trackActive(1)
+	}
+	t.fireAt = t.finishAt.Add(t.delay())
+	if t.driftCheck > 0 {
+		// compare with wall clock instead of monotonic one
+		t.fireAt = t.fireAt.Round(0)
+	}
+	t.wait(gen)
+}
+
+// Must be called with locked mutex
+func (t *ticker[T]) wait(gen uint64) {
+	t.timer = t.armAt(t.fireAt, func() {
+		t.fire(gen)
+	}, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if gen == t.gen && t.state == StatePending {
+			t.wait(gen)
+		}
+	})
+}
+
+func (t *ticker[T]) fire(gen uint64) {
+	t.mu.Lock()
+	if gen != t.gen || t.state != StatePending {
+		t.mu.Unlock()
 		return
 	}
-	if t.ticker != nil {
-		d := t.finishAt.Sub(now)
-		t.ticker.Reset(d)
-	} else {
-		t.Start()
+	t.state = StateFired
+	t.deactivate()
+	ctx := t.ctx
+	delay := t.now().Sub(t.fireAt)
+	t.mu.Unlock()
+	t.finish(ctx, delay)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// ticker re-armed by callback keeps its context
+	if gen == t.gen {
+		t.release()
+	}
+}
+
+func (t *ticker[T]) finish(ctx context.Context, delay time.Duration) {
+	if ctx.Err() != nil {
+		return
+	}
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	run(&t.config, t.id, func() error {
+		if err := t.onFinish(ctx, t.id); err != nil {
+			return err
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return ctx.Err()
+		}
+		return nil
+	})
+	t.trackFired(delay, time.Since(start))
+}
+
+// Callback without context can't observe deadline, so WithTimeout is not applied
+func (t *ticker[T]) ignoreTimeout() {
+	t.timeout = 0
+}
+
+// Must be called with locked mutex
+func (t *ticker[T]) deactivate() {
+	if t.active {
+		t.active = false
+		t.trackActive(-1)
+	}
+}
+
+// Creates context of the ticker for the next fire and stops ticker when parent
+// context is done. Must be called with locked mutex
+func (t *ticker[T]) bind() {
+	if t.cancel != nil {
+		return
+	}
+	t.ctx, t.cancel = context.WithCancel(t.parent)
+	t.unwatch = context.AfterFunc(t.parent, t.Stop)
+}
+
+// Cancels context of the ticker and unregisters it from parent context,
+// so fired or stopped ticker is not retained by parent. Must be called with locked mutex
+func (t *ticker[T]) release() {
+	if t.cancel == nil {
+		return
 	}
+	t.unwatch()
+	t.cancel()
+	t.unwatch = nil
+	t.cancel = nil
 }
@@ -0,0 +1,113 @@
+package ticker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Parent context counting registrations of children and AfterFunc callbacks
+type trackingContext struct {
+	context.Context
+	mu    sync.Mutex
+	done  chan struct{}
+	next  int
+	funcs map[int]func()
+}
+
+func newTrackingContext() *trackingContext {
+	return &trackingContext{
+		Context: context.Background(),
+		done:    make(chan struct{}),
+		funcs:   make(map[int]func()),
+	}
+}
+
+func (c *trackingContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *trackingContext) AfterFunc(f func()) func() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.next++
+	id := c.next
+	c.funcs[id] = f
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		_, ok := c.funcs[id]
+		delete(c.funcs, id)
+		return ok
+	}
+}
+
+func (c *trackingContext) retained() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.funcs)
+}
+
+// Waits until parent retains nothing
+func waitReleased(t *testing.T, parent *trackingContext) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for parent.retained() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := parent.retained(); n > 0 {
+		t.Fatalf("parent context retains %d registrations", n)
+	}
+}
+
+func TestFiredTickersReleaseParentContext(t *testing.T) {
+	const n = 100
+	parent := newTrackingContext()
+	var fired sync.WaitGroup
+	fired.Add(n)
+	for i := 0; i < n; i++ {
+		NewWithContext(parent, i, time.Now(), func(ctx context.Context, id int) {
+			fired.Done()
+		}).Start()
+	}
+	fired.Wait()
+	waitReleased(t, parent)
+}
+
+func TestExtendRearmsFiredTicker(t *testing.T) {
+	type call struct {
+		ctx context.Context
+		err error
+	}
+	parent := newTrackingContext()
+	calls := make(chan call, 2)
+	tk := NewWithContext(parent, 1, time.Now(), func(ctx context.Context, id int) {
+		calls <- call{ctx, ctx.Err()}
+	})
+	tk.Start()
+	first := <-calls
+	waitReleased(t, parent)
+	if first.ctx.Err() == nil {
+		t.Fatal("context of fired ticker is not canceled")
+	}
+
+	tk.Extend(0)
+	second := <-calls
+	if second.err != nil {
+		t.Fatalf("re-armed ticker is called with canceled context: %v", second.err)
+	}
+	waitReleased(t, parent)
+	if tk.State() != StateFired {
+		t.Fatalf("state is %s, want fired", tk.State())
+	}
+}
+
+func TestOnErrorTypeMismatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("constructor doesn't panic for OnError hook of another ID type")
+		}
+	}()
+	New(1, time.Now(), func(id int) {}, OnError(func(id string, recovered any) {}))
+}
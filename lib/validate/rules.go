@@ -0,0 +1,246 @@
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+)
+
+// Checks value and returns message of violation or empty string if value is valid.
+// Value is nil for absent map keys and nil pointers
+type Rule func(value any) string
+
+// Builds rule from parameter of struct tag
+type RuleBuilder func(param string) (Rule, error)
+
+var (
+	buildersMu sync.RWMutex
+	builders   = map[string]RuleBuilder{
+		"required": func(string) (Rule, error) { return Required(), nil },
+		"email":    func(string) (Rule, error) { return Email(), nil },
+		"url":      func(string) (Rule, error) { return URL(), nil },
+		"min":      numberParam(Min),
+		"max":      numberParam(Max),
+		"len": func(param string) (Rule, error) {
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				return nil, err
+			}
+			return Len(n), nil
+		},
+		"oneof": func(param string) (Rule, error) { return OneOf(strings.Fields(param)...), nil },
+		"regexp": func(param string) (Rule, error) {
+			re, err := regexp.Compile(param)
+			if err != nil {
+				return nil, err
+			}
+			return Match(re), nil
+		},
+	}
+)
+
+// Registers rule for struct tags. Existing rule with the same name is replaced
+//
+// Ex.: validate.RegisterRule("even", func(string) (validate.Rule, error) { return isEven, nil })
+func RegisterRule(name string, build RuleBuilder) {
+	buildersMu.Lock()
+	defer buildersMu.Unlock()
+	builders[name] = build
+}
+
+// Value must be present and not zero. Empty strings, slices and maps are invalid
+func Required() Rule {
+	return func(value any) string {
+		v := indirect(value)
+		if !v.IsValid() || v.IsZero() || (hasLen(v) && v.Len() == 0) {
+			return "required"
+		}
+		return ""
+	}
+}
+
+// String must be email address
+func Email() Rule {
+	return stringRule(func(s string) string {
+		addr, err := mail.ParseAddress(s)
+		if err != nil || addr.Address != s {
+			return "must be a valid email"
+		}
+		return ""
+	})
+}
+
+// String must be absolute URL
+func URL() Rule {
+	return stringRule(func(s string) string {
+		u, err := url.ParseRequestURI(s)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be a valid URL"
+		}
+		return ""
+	})
+}
+
+// Number must be at least n. Length of string, slice or map must be at least n
+func Min(n float64) Rule {
+	return compareRule(n, func(x float64) bool { return x >= n }, "must be at least %v", "length must be at least %v")
+}
+
+// Number must be at most n. Length of string, slice or map must be at most n
+func Max(n float64) Rule {
+	return compareRule(n, func(x float64) bool { return x <= n }, "must be at most %v", "length must be at most %v")
+}
+
+// Length of string, slice or map must be n
+func Len(n int) Rule {
+	return func(value any) string {
+		v := indirect(value)
+		if skip(v) || !hasLen(v) {
+			return ""
+		}
+		if length(v) != n {
+			return fmt.Sprintf("length must be %d", n)
+		}
+		return ""
+	}
+}
+
+// Value formatted as string must be one of values
+func OneOf(values ...string) Rule {
+	return func(value any) string {
+		v := indirect(value)
+		if skip(v) {
+			return ""
+		}
+		if !slices.Contains(values, fmt.Sprint(v.Interface())) {
+			return "must be one of " + strings.Join(values, ", ")
+		}
+		return ""
+	}
+}
+
+// String must match regular expression
+func Match(re *regexp.Regexp) Rule {
+	return stringRule(func(s string) string {
+		if !re.MatchString(s) {
+			return "must match " + re.String()
+		}
+		return ""
+	})
+}
+
+// Parses rules of struct tag
+func parseTag(tag string) ([]Rule, error) {
+	var rules []Rule
+	for _, item := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(item), "=")
+		if name == "" {
+			continue
+		}
+		buildersMu.RLock()
+		build, ok := builders[name]
+		buildersMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown validation rule %q", jve.ErrInternal, name)
+		}
+		rule, err := build(param)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad parameter of validation rule %q: %v", jve.ErrInternal, name, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func numberParam(rule func(n float64) Rule) RuleBuilder {
+	return func(param string) (Rule, error) {
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return nil, err
+		}
+		return rule(n), nil
+	}
+}
+
+func stringRule(check func(s string) string) Rule {
+	return func(value any) string {
+		v := indirect(value)
+		if skip(v) {
+			return ""
+		}
+		if v.Kind() != reflect.String {
+			return "must be a string"
+		}
+		return check(v.String())
+	}
+}
+
+func compareRule(n float64, ok func(x float64) bool, numberMsg string, lenMsg string) Rule {
+	return func(value any) string {
+		v := indirect(value)
+		if skip(v) {
+			return ""
+		}
+		switch {
+		case hasLen(v):
+			if !ok(float64(length(v))) {
+				return fmt.Sprintf(lenMsg, n)
+			}
+		case v.CanInt():
+			if !ok(float64(v.Int())) {
+				return fmt.Sprintf(numberMsg, n)
+			}
+		case v.CanUint():
+			if !ok(float64(v.Uint())) {
+				return fmt.Sprintf(numberMsg, n)
+			}
+		case v.CanFloat():
+			if !ok(v.Float()) {
+				return fmt.Sprintf(numberMsg, n)
+			}
+		}
+		return ""
+	}
+}
+
+// Dereferences pointers and interfaces
+func indirect(value any) reflect.Value {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// Rules except Required skip absent values and empty strings
+func skip(v reflect.Value) bool {
+	return !v.IsValid() || (v.Kind() == reflect.String && v.Len() == 0)
+}
+
+func hasLen(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// Length of collection. Length of string is count of runes
+func length(v reflect.Value) int {
+	if v.Kind() == reflect.String {
+		return utf8.RuneCountInString(v.String())
+	}
+	return v.Len()
+}
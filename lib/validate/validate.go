@@ -0,0 +1,150 @@
+package validate
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	jve "github.com/jvnonce/jv-go-utils/lib/errors"
+	jvm "github.com/jvnonce/jv-go-utils/lib/maps"
+)
+
+// Rules of map keys
+type Fields map[string][]Rule
+
+// Implemented by values with custom validation, which is called after rules of tags
+type Validator interface {
+	Validate() error
+}
+
+type structField struct {
+	index int
+	name  string
+	rules []Rule
+}
+
+// Parsed fields of struct types
+var structCache sync.Map
+
+// Validates struct by rules of validate tags. Nested structs and slices of structs are validated too.
+// Fields are named by json tags. Returns *errors.Validation with messages per field or nil
+//
+// Ex.: type User struct { Email string `json:"email" validate:"required,email,max=50"` }; err := validate.Struct(&user)
+func Struct(v any) error {
+	rv := indirect(v)
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	result := jve.NewValidation()
+	if err := validateStruct(rv, "", result); err != nil {
+		return err
+	}
+	return result.Err()
+}
+
+// Validates map by rules of keys. Absent keys are validated as nil. Fields are checked in sorted order
+//
+// Ex.: validate.Map(m, validate.Fields{"email": {validate.Required(), validate.Email()}})
+func Map(m jvm.M, fields Fields) error {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	result := jve.NewValidation()
+	for _, name := range names {
+		check(m[name], name, fields[name], result)
+	}
+	return result.Err()
+}
+
+// Validates struct by tags and calls Validate of values implementing Validator.
+// Other values are valid
+//
+// Ex.: if err := validate.Value(v); err != nil { return err }
+func Value(v any) error {
+	if err := Struct(v); err != nil {
+		return err
+	}
+	if validator, ok := v.(Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+func validateStruct(rv reflect.Value, prefix string, result *jve.Validation) error {
+	fields, err := parseStruct(rv.Type())
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		value := rv.Field(f.index)
+		name := prefix + f.name
+		check(value.Interface(), name, f.rules, result)
+		if err := validateNested(indirect(value.Interface()), name, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateNested(v reflect.Value, name string, result *jve.Validation) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		return validateStruct(v, name+".", result)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			item := indirect(v.Index(i).Interface())
+			if item.Kind() == reflect.Struct {
+				if err := validateStruct(item, name+"."+strconv.Itoa(i)+".", result); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func check(value any, name string, rules []Rule, result *jve.Validation) {
+	for _, rule := range rules {
+		if message := rule(value); message != "" {
+			result.Add(name, message)
+		}
+	}
+}
+
+// Returns exported fields of struct type with their names and rules
+func parseStruct(t reflect.Type) ([]structField, error) {
+	if cached, ok := structCache.Load(t); ok {
+		return cached.([]structField), nil
+	}
+	fields := make([]structField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		rules, err := parseTag(sf.Tag.Get("validate"))
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, structField{
+			index: i,
+			name:  fieldName(sf),
+			rules: rules,
+		})
+	}
+	structCache.Store(t, fields)
+	return fields, nil
+}
+
+// Name of field in json tag or name of field
+func fieldName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return sf.Name
+	}
+	return name
+}